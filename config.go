@@ -0,0 +1,535 @@
+package noob
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	keyvalue "github.com/alfarih31/nb-go-keyvalue"
+	"github.com/alfarih31/nb-go-parser"
+	"gopkg.in/yaml.v2"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultEnvPrefix is prefix of env keys read by LoadConfig
+const DefaultEnvPrefix = "SERVER_"
+
+// Config is aggregate of all application configuration
+type Config struct {
+	Cfg           Cfg
+	CORSCfg       CORSCfg
+	ThrottlingCfg ThrottlingCfg
+	Meta          keyvalue.KeyValue
+}
+
+// LoadConfigOptions is options for LoadConfig
+type LoadConfigOptions struct {
+	// EnvPrefix is prefix of env keys, default to DefaultEnvPrefix
+	EnvPrefix string
+
+	// Files is list of JSON (.json) or YAML (.yaml, .yml) config files. Latter files override former files
+	Files []string
+
+	// IgnoreMissingFiles skip files which are not exist instead of returning error
+	IgnoreMissingFiles bool
+}
+
+// LoadConfig load Config with following precedence order (latter override former):
+//  1. Package defaults: DefaultCfg, DefaultCORSCfg, DefaultThrottlingCfg & DefaultMeta
+//  2. Config files, in order of LoadConfigOptions.Files
+//  3. Env, see below keys (prefixed by LoadConfigOptions.EnvPrefix)
+//
+// Env keys:
+//
+//	HOST, PORT, PATH, REQUEST_TIMEOUT, USE_LISTENER, SHUTDOWN_TIMEOUT, HANDLE_SIGNAL, H2C, MAX_BODY_SIZE (bytes),
+//	TLS_CERT_FILE, TLS_KEY_FILE, TLS_MIN_VERSION (e.g. 1.2), TLS_CLIENT_CA_FILE, TLS_CLIENT_AUTH (see ParseTLSClientAuth),
+//	CORS_ENABLE, CORS_ALLOW_ORIGINS (comma separated), CORS_ALLOW_METHODS, CORS_ALLOW_HEADERS,
+//	CORS_ALLOW_CREDENTIALS, CORS_EXPOSE_HEADERS, CORS_MAX_AGE,
+//	THROTTLING_ENABLE, THROTTLING_MAX_EVENT_PER_SEC, THROTTLING_MAX_BURST_SIZE,
+//	APP_NAME, APP_DESCRIPTION, APP_VERSION
+//
+// Durations are written as Go duration string, e.g. "10s", or as number of seconds.
+// Loaded Config is validated, the returned error is Errors containing every violation
+func LoadConfig(opts ...LoadConfigOptions) (Config, error) {
+	var opt LoadConfigOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	if opt.EnvPrefix == "" {
+		opt.EnvPrefix = DefaultEnvPrefix
+	}
+
+	c := Config{
+		Cfg:           DefaultCfg,
+		CORSCfg:       DefaultCORSCfg.copy(),
+		ThrottlingCfg: DefaultThrottlingCfg,
+		Meta:          copyKeyValue(DefaultMeta),
+	}
+
+	var errs Errors
+	for _, f := range opt.Files {
+		if err := c.loadFile(f); err != nil {
+			if os.IsNotExist(err) && opt.IgnoreMissingFiles {
+				continue
+			}
+
+			errs = append(errs, NewCoreError(fmt.Sprintf("config: load file %s error, %v", f, err)))
+		}
+	}
+
+	errs = append(errs, c.loadEnv(opt.EnvPrefix)...)
+
+	if len(errs) > 0 {
+		return c, errs
+	}
+
+	return c, c.Validate()
+}
+
+// Validate validate all configuration, the returned error is Errors containing every violation
+func (c Config) Validate() error {
+	var errs Errors
+	errs = append(errs, c.Cfg.validate()...)
+	errs = append(errs, c.CORSCfg.validate()...)
+	errs = append(errs, c.ThrottlingCfg.validate()...)
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// SetDefaults assign Config to package defaults: DefaultCfg, DefaultCORSCfg, DefaultThrottlingCfg & DefaultMeta
+func (c Config) SetDefaults() {
+	DefaultCfg = c.Cfg
+	DefaultCORSCfg = c.CORSCfg.copy()
+	DefaultThrottlingCfg = c.ThrottlingCfg
+	DefaultMeta = copyKeyValue(c.Meta)
+}
+
+// WithConfig set all configuration of the application
+func WithConfig(c Config) Option {
+	return func(co *Ctx) {
+		co.Cfg = c.Cfg
+		co.CORSCfg = c.CORSCfg.copy()
+		co.ThrottlingCfg = c.ThrottlingCfg
+		co.Meta = copyKeyValue(c.Meta)
+	}
+}
+
+func newConfigError(field string, msg string) *CoreError {
+	return NewCoreError(fmt.Sprintf("config: %s %s", field, msg), keyvalue.KeyValue{"field": field})
+}
+
+func (c Cfg) validate() Errors {
+	var errs Errors
+	if c.Port < 0 || c.Port > 65535 {
+		errs = append(errs, newConfigError("Cfg.Port", fmt.Sprintf("must be between 0 and 65535, got %d", c.Port)))
+	}
+
+	if c.Path != "" && !strings.HasPrefix(c.Path, "/") {
+		errs = append(errs, newConfigError("Cfg.Path", fmt.Sprintf("must start with '/', got '%s'", c.Path)))
+	}
+
+	if c.RequestTimeout < 0 {
+		errs = append(errs, newConfigError("Cfg.RequestTimeout", "must not be negative"))
+	}
+
+	if c.ShutdownTimeout < 0 {
+		errs = append(errs, newConfigError("Cfg.ShutdownTimeout", "must not be negative"))
+	}
+
+	if c.MaxBodySize < 0 {
+		errs = append(errs, newConfigError("Cfg.MaxBodySize", "must not be negative"))
+	}
+
+	errs = append(errs, c.TLS.validate()...)
+
+	return errs
+}
+
+func (c CORSCfg) validate() Errors {
+	var errs Errors
+	for _, o := range c.AllowOrigins {
+		if o == "*" {
+			continue
+		}
+
+		u, err := url.Parse(o)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+			errs = append(errs, newConfigError("CORSCfg.AllowOrigins", fmt.Sprintf("has malformed origin '%s'", o)))
+		}
+	}
+
+	if c.MaxAge < 0 {
+		errs = append(errs, newConfigError("CORSCfg.MaxAge", "must not be negative"))
+	}
+
+	return errs
+}
+
+func (c ThrottlingCfg) validate() Errors {
+	if !c.Enable {
+		return nil
+	}
+
+	var errs Errors
+	if c.MaxEventPerSec <= 0 {
+		errs = append(errs, newConfigError("ThrottlingCfg.MaxEventPerSec", "must be positive"))
+	}
+
+	if c.MaxBurstSize <= 0 {
+		errs = append(errs, newConfigError("ThrottlingCfg.MaxBurstSize", "must be positive"))
+	}
+
+	return errs
+}
+
+// configDuration is time.Duration written as Go duration string or number of seconds
+type configDuration time.Duration
+
+func parseConfigDuration(v interface{}) (configDuration, error) {
+	switch d := v.(type) {
+	case string:
+		if f, err := parser.String(d).ToInt(); err == nil {
+			return configDuration(time.Duration(f) * time.Second), nil
+		}
+
+		pd, err := time.ParseDuration(d)
+		return configDuration(pd), err
+	case int:
+		return configDuration(time.Duration(d) * time.Second), nil
+	case float64:
+		return configDuration(time.Duration(d * float64(time.Second))), nil
+	}
+
+	return 0, fmt.Errorf("invalid duration %v", v)
+}
+
+func (d *configDuration) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	pd, err := parseConfigDuration(v)
+	if err != nil {
+		return err
+	}
+
+	*d = pd
+	return nil
+}
+
+func (d *configDuration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v interface{}
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+
+	pd, err := parseConfigDuration(v)
+	if err != nil {
+		return err
+	}
+
+	*d = pd
+	return nil
+}
+
+// fileConfig is schema of config file, nil value means not set
+type fileConfig struct {
+	Server *struct {
+		Host            *string         `json:"host" yaml:"host"`
+		Port            *int            `json:"port" yaml:"port"`
+		Path            *string         `json:"path" yaml:"path"`
+		RequestTimeout  *configDuration `json:"request_timeout" yaml:"request_timeout"`
+		UseListener     *bool           `json:"use_listener" yaml:"use_listener"`
+		ShutdownTimeout *configDuration `json:"shutdown_timeout" yaml:"shutdown_timeout"`
+		HandleSignal    *bool           `json:"handle_signal" yaml:"handle_signal"`
+		H2C             *bool           `json:"h2c" yaml:"h2c"`
+		MaxBodySize     *int            `json:"max_body_size" yaml:"max_body_size"`
+		TLS             *struct {
+			CertFile     *string `json:"cert_file" yaml:"cert_file"`
+			KeyFile      *string `json:"key_file" yaml:"key_file"`
+			MinVersion   *string `json:"min_version" yaml:"min_version"`
+			ClientCAFile *string `json:"client_ca_file" yaml:"client_ca_file"`
+			ClientAuth   *string `json:"client_auth" yaml:"client_auth"`
+		} `json:"tls" yaml:"tls"`
+	} `json:"server" yaml:"server"`
+	CORS *struct {
+		Enable           *bool           `json:"enable" yaml:"enable"`
+		AllowOrigins     []string        `json:"allow_origins" yaml:"allow_origins"`
+		AllowMethods     *string         `json:"allow_methods" yaml:"allow_methods"`
+		AllowHeaders     *string         `json:"allow_headers" yaml:"allow_headers"`
+		AllowCredentials *bool           `json:"allow_credentials" yaml:"allow_credentials"`
+		ExposeHeaders    *string         `json:"expose_headers" yaml:"expose_headers"`
+		MaxAge           *configDuration `json:"max_age" yaml:"max_age"`
+	} `json:"cors" yaml:"cors"`
+	Throttling *struct {
+		Enable         *bool `json:"enable" yaml:"enable"`
+		MaxEventPerSec *int  `json:"max_event_per_sec" yaml:"max_event_per_sec"`
+		MaxBurstSize   *int  `json:"max_burst_size" yaml:"max_burst_size"`
+	} `json:"throttling" yaml:"throttling"`
+	Meta map[string]interface{} `json:"meta" yaml:"meta"`
+}
+
+func (c *Config) loadFile(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var fc fileConfig
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(b, &fc)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &fc)
+	default:
+		err = fmt.Errorf("unsupported config file extension '%s'", filepath.Ext(path))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if s := fc.Server; s != nil {
+		if s.Host != nil {
+			c.Cfg.Host = *s.Host
+		}
+		if s.Port != nil {
+			c.Cfg.Port = *s.Port
+		}
+		if s.Path != nil {
+			c.Cfg.Path = *s.Path
+		}
+		if s.RequestTimeout != nil {
+			c.Cfg.RequestTimeout = time.Duration(*s.RequestTimeout)
+		}
+		if s.UseListener != nil {
+			c.Cfg.UseListener = *s.UseListener
+		}
+		if s.ShutdownTimeout != nil {
+			c.Cfg.ShutdownTimeout = time.Duration(*s.ShutdownTimeout)
+		}
+		if s.HandleSignal != nil {
+			c.Cfg.HandleSignal = *s.HandleSignal
+		}
+		if s.H2C != nil {
+			c.Cfg.H2C = *s.H2C
+		}
+		if s.MaxBodySize != nil {
+			c.Cfg.MaxBodySize = *s.MaxBodySize
+		}
+		if t := s.TLS; t != nil {
+			if t.CertFile != nil {
+				c.Cfg.TLS.CertFile = *t.CertFile
+			}
+			if t.KeyFile != nil {
+				c.Cfg.TLS.KeyFile = *t.KeyFile
+			}
+			if t.MinVersion != nil {
+				if c.Cfg.TLS.MinVersion, err = ParseTLSVersion(*t.MinVersion); err != nil {
+					return err
+				}
+			}
+			if t.ClientCAFile != nil {
+				c.Cfg.TLS.ClientCAFile = *t.ClientCAFile
+			}
+			if t.ClientAuth != nil {
+				if c.Cfg.TLS.ClientAuth, err = ParseTLSClientAuth(*t.ClientAuth); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if s := fc.CORS; s != nil {
+		if s.Enable != nil {
+			c.CORSCfg.Enable = *s.Enable
+		}
+		if s.AllowOrigins != nil {
+			c.CORSCfg.AllowOrigins = s.AllowOrigins
+		}
+		if s.AllowMethods != nil {
+			c.CORSCfg.AllowMethods = *s.AllowMethods
+		}
+		if s.AllowHeaders != nil {
+			c.CORSCfg.AllowHeaders = *s.AllowHeaders
+		}
+		if s.AllowCredentials != nil {
+			c.CORSCfg.AllowCredentials = *s.AllowCredentials
+		}
+		if s.ExposeHeaders != nil {
+			c.CORSCfg.ExposeHeaders = *s.ExposeHeaders
+		}
+		if s.MaxAge != nil {
+			c.CORSCfg.MaxAge = time.Duration(*s.MaxAge)
+		}
+	}
+
+	if s := fc.Throttling; s != nil {
+		if s.Enable != nil {
+			c.ThrottlingCfg.Enable = *s.Enable
+		}
+		if s.MaxEventPerSec != nil {
+			c.ThrottlingCfg.MaxEventPerSec = *s.MaxEventPerSec
+		}
+		if s.MaxBurstSize != nil {
+			c.ThrottlingCfg.MaxBurstSize = *s.MaxBurstSize
+		}
+	}
+
+	if fc.Meta != nil {
+		if c.Meta == nil {
+			c.Meta = keyvalue.KeyValue{}
+		}
+
+		for k, v := range fc.Meta {
+			c.Meta[k] = v
+		}
+	}
+
+	return nil
+}
+
+// envLoader read prefixed env & collect parsing errors
+type envLoader struct {
+	prefix string
+	errs   Errors
+}
+
+func (l *envLoader) lookup(key string) (string, bool) {
+	return os.LookupEnv(l.prefix + key)
+}
+
+func (l *envLoader) string(key string, target *string) {
+	if v, ok := l.lookup(key); ok {
+		*target = v
+	}
+}
+
+func (l *envLoader) int(key string, target *int) {
+	if v, ok := l.lookup(key); ok {
+		i, err := parser.String(v).ToInt()
+		if err != nil {
+			l.errs = append(l.errs, newConfigError(l.prefix+key, fmt.Sprintf("must be integer, got '%s'", v)))
+			return
+		}
+
+		*target = i
+	}
+}
+
+func (l *envLoader) bool(key string, target *bool) {
+	if v, ok := l.lookup(key); ok {
+		b, err := parser.String(v).ToBool()
+		if err != nil {
+			l.errs = append(l.errs, newConfigError(l.prefix+key, fmt.Sprintf("must be boolean, got '%s'", v)))
+			return
+		}
+
+		*target = b
+	}
+}
+
+func (l *envLoader) duration(key string, target *time.Duration) {
+	if v, ok := l.lookup(key); ok {
+		d, err := parseConfigDuration(v)
+		if err != nil {
+			l.errs = append(l.errs, newConfigError(l.prefix+key, fmt.Sprintf("must be duration, got '%s'", v)))
+			return
+		}
+
+		*target = time.Duration(d)
+	}
+}
+
+func (l *envLoader) strings(key string, target *[]string) {
+	if v, ok := l.lookup(key); ok {
+		var ss []string
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				ss = append(ss, s)
+			}
+		}
+
+		*target = ss
+	}
+}
+
+func (l *envLoader) tlsVersion(key string, target *uint16) {
+	if v, ok := l.lookup(key); ok {
+		ver, err := ParseTLSVersion(v)
+		if err != nil {
+			l.errs = append(l.errs, newConfigError(l.prefix+key, err.Error()))
+			return
+		}
+
+		*target = ver
+	}
+}
+
+func (l *envLoader) tlsClientAuth(key string, target *tls.ClientAuthType) {
+	if v, ok := l.lookup(key); ok {
+		a, err := ParseTLSClientAuth(v)
+		if err != nil {
+			l.errs = append(l.errs, newConfigError(l.prefix+key, err.Error()))
+			return
+		}
+
+		*target = a
+	}
+}
+
+func (l *envLoader) meta(key string, metaKey string, target *keyvalue.KeyValue) {
+	if v, ok := l.lookup(key); ok {
+		if *target == nil {
+			*target = keyvalue.KeyValue{}
+		}
+
+		(*target)[metaKey] = v
+	}
+}
+
+func (c *Config) loadEnv(prefix string) Errors {
+	l := &envLoader{prefix: prefix}
+
+	l.string("HOST", &c.Cfg.Host)
+	l.int("PORT", &c.Cfg.Port)
+	l.string("PATH", &c.Cfg.Path)
+	l.duration("REQUEST_TIMEOUT", &c.Cfg.RequestTimeout)
+	l.bool("USE_LISTENER", &c.Cfg.UseListener)
+	l.duration("SHUTDOWN_TIMEOUT", &c.Cfg.ShutdownTimeout)
+	l.bool("HANDLE_SIGNAL", &c.Cfg.HandleSignal)
+	l.bool("H2C", &c.Cfg.H2C)
+	l.int("MAX_BODY_SIZE", &c.Cfg.MaxBodySize)
+	l.string("TLS_CERT_FILE", &c.Cfg.TLS.CertFile)
+	l.string("TLS_KEY_FILE", &c.Cfg.TLS.KeyFile)
+	l.tlsVersion("TLS_MIN_VERSION", &c.Cfg.TLS.MinVersion)
+	l.string("TLS_CLIENT_CA_FILE", &c.Cfg.TLS.ClientCAFile)
+	l.tlsClientAuth("TLS_CLIENT_AUTH", &c.Cfg.TLS.ClientAuth)
+
+	l.bool("CORS_ENABLE", &c.CORSCfg.Enable)
+	l.strings("CORS_ALLOW_ORIGINS", &c.CORSCfg.AllowOrigins)
+	l.string("CORS_ALLOW_METHODS", &c.CORSCfg.AllowMethods)
+	l.string("CORS_ALLOW_HEADERS", &c.CORSCfg.AllowHeaders)
+	l.bool("CORS_ALLOW_CREDENTIALS", &c.CORSCfg.AllowCredentials)
+	l.string("CORS_EXPOSE_HEADERS", &c.CORSCfg.ExposeHeaders)
+	l.duration("CORS_MAX_AGE", &c.CORSCfg.MaxAge)
+
+	l.bool("THROTTLING_ENABLE", &c.ThrottlingCfg.Enable)
+	l.int("THROTTLING_MAX_EVENT_PER_SEC", &c.ThrottlingCfg.MaxEventPerSec)
+	l.int("THROTTLING_MAX_BURST_SIZE", &c.ThrottlingCfg.MaxBurstSize)
+
+	l.meta("APP_NAME", "app_name", &c.Meta)
+	l.meta("APP_DESCRIPTION", "app_description", &c.Meta)
+	l.meta("APP_VERSION", "app_version", &c.Meta)
+
+	return l.errs
+}
@@ -0,0 +1,178 @@
+package noob
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const configTestEnvPrefix = "NOOB_CONFIG_TEST_"
+
+func TestLoadConfigDefaults(t *testing.T) {
+	c, err := LoadConfig(LoadConfigOptions{EnvPrefix: configTestEnvPrefix})
+	if err != nil {
+		t.Fatalf("load config error: %v", err)
+	}
+
+	if c.Cfg != DefaultCfg || c.CORSCfg.AllowMethods != DefaultCORSCfg.AllowMethods || c.ThrottlingCfg != DefaultThrottlingCfg {
+		t.Fatalf("expected defaults, got %+v", c)
+	}
+}
+
+func TestLoadConfigJSON(t *testing.T) {
+	c, err := LoadConfig(LoadConfigOptions{EnvPrefix: configTestEnvPrefix, Files: []string{"testdata/config.json"}})
+	if err != nil {
+		t.Fatalf("load config error: %v", err)
+	}
+
+	if c.Cfg.Host != "127.0.0.1" || c.Cfg.Port != 9000 || c.Cfg.Path != "/api" || c.Cfg.MaxBodySize != 2048 {
+		t.Fatalf("unexpected Cfg %+v", c.Cfg)
+	}
+
+	// Duration is Go duration string or number of seconds
+	if c.Cfg.RequestTimeout != 15*time.Second || c.Cfg.ShutdownTimeout != 30*time.Second || c.CORSCfg.MaxAge != time.Hour {
+		t.Fatalf("unexpected durations %+v %+v", c.Cfg, c.CORSCfg)
+	}
+
+	if !reflect.DeepEqual(c.CORSCfg.AllowOrigins, []string{"https://example.com"}) || c.CORSCfg.AllowHeaders != DefaultCORSCfg.AllowHeaders {
+		t.Fatalf("unexpected CORSCfg %+v", c.CORSCfg)
+	}
+
+	if !c.ThrottlingCfg.Enable || c.ThrottlingCfg.MaxEventPerSec != 50 || c.ThrottlingCfg.MaxBurstSize != 100 {
+		t.Fatalf("unexpected ThrottlingCfg %+v", c.ThrottlingCfg)
+	}
+
+	if c.Meta["app_name"] != "json-app" {
+		t.Fatalf("unexpected Meta %v", c.Meta)
+	}
+}
+
+func TestLoadConfigYAML(t *testing.T) {
+	c, err := LoadConfig(LoadConfigOptions{EnvPrefix: configTestEnvPrefix, Files: []string{"testdata/config.yaml"}})
+	if err != nil {
+		t.Fatalf("load config error: %v", err)
+	}
+
+	if c.Cfg.Port != 9100 || c.Cfg.RequestTimeout != 20*time.Second || c.Cfg.Path != DefaultCfg.Path {
+		t.Fatalf("unexpected Cfg %+v", c.Cfg)
+	}
+
+	if !reflect.DeepEqual(c.CORSCfg.AllowOrigins, []string{"https://example.org"}) || c.CORSCfg.AllowCredentials {
+		t.Fatalf("unexpected CORSCfg %+v", c.CORSCfg)
+	}
+
+	if c.Meta["app_version"] != "1.2.3" {
+		t.Fatalf("unexpected Meta %v", c.Meta)
+	}
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	t.Setenv("SERVER_PORT", "9200")
+	t.Setenv("SERVER_CORS_ALLOW_ORIGINS", "https://a.example.com, https://b.example.com")
+	t.Setenv("SERVER_APP_NAME", "env-app")
+
+	// Defaults < config.json < config.yaml < env
+	c, err := LoadConfig(LoadConfigOptions{Files: []string{"testdata/config.json", "testdata/config.yaml"}})
+	if err != nil {
+		t.Fatalf("load config error: %v", err)
+	}
+
+	if c.Cfg.Port != 9200 || c.Cfg.RequestTimeout != 20*time.Second || c.Cfg.Host != "127.0.0.1" || c.Cfg.ShutdownTimeout != 30*time.Second {
+		t.Fatalf("unexpected Cfg %+v", c.Cfg)
+	}
+
+	if !reflect.DeepEqual(c.CORSCfg.AllowOrigins, []string{"https://a.example.com", "https://b.example.com"}) {
+		t.Fatalf("unexpected AllowOrigins %v", c.CORSCfg.AllowOrigins)
+	}
+
+	if c.Meta["app_name"] != "env-app" || c.Meta["app_version"] != "1.2.3" {
+		t.Fatalf("unexpected Meta %v", c.Meta)
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	if _, err := LoadConfig(LoadConfigOptions{EnvPrefix: configTestEnvPrefix, Files: []string{"testdata/missing.json"}}); err == nil {
+		t.Fatal("expected error of missing file")
+	}
+
+	opt := LoadConfigOptions{EnvPrefix: configTestEnvPrefix, Files: []string{"testdata/missing.json"}, IgnoreMissingFiles: true}
+	if _, err := LoadConfig(opt); err != nil {
+		t.Fatalf("expected missing file ignored, got %v", err)
+	}
+}
+
+// expectConfigError assert err is Errors of a CoreError per field
+func expectConfigError(t *testing.T, err error, fields ...string) {
+	t.Helper()
+
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != len(fields) {
+		t.Fatalf("expected Errors of %v, got %v", fields, err)
+	}
+
+	for i, f := range fields {
+		var ce *CoreError
+		if !errors.As(errs[i], &ce) || !strings.HasPrefix(ce.Error(), "config: "+f+" ") {
+			t.Fatalf("expected CoreError of %s, got %v", f, errs[i])
+		}
+	}
+}
+
+func TestLoadConfigValidation(t *testing.T) {
+	cases := []struct {
+		name  string
+		key   string
+		value string
+		field string
+	}{
+		{"port", "PORT", "70000", "Cfg.Port"},
+		{"negative timeout", "REQUEST_TIMEOUT", "-1s", "Cfg.RequestTimeout"},
+		{"malformed origin", "CORS_ALLOW_ORIGINS", "example.com", "CORSCfg.AllowOrigins"},
+		{"unparsable env", "PORT", "eighty", configTestEnvPrefix + "PORT"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(configTestEnvPrefix+tc.key, tc.value)
+
+			_, err := LoadConfig(LoadConfigOptions{EnvPrefix: configTestEnvPrefix})
+			expectConfigError(t, err, tc.field)
+		})
+	}
+}
+
+func TestConfigValidateEveryViolation(t *testing.T) {
+	c := Config{
+		Cfg:           Cfg{Port: -1, ShutdownTimeout: -time.Second},
+		ThrottlingCfg: ThrottlingCfg{Enable: true, MaxEventPerSec: 0, MaxBurstSize: 1},
+	}
+
+	expectConfigError(t, c.Validate(), "Cfg.Port", "Cfg.ShutdownTimeout", "ThrottlingCfg.MaxEventPerSec")
+}
+
+func TestParseConfigDuration(t *testing.T) {
+	cases := []struct {
+		v    interface{}
+		want time.Duration
+	}{
+		{"1m30s", 90 * time.Second},
+		{"10", 10 * time.Second},
+		{5, 5 * time.Second},
+		{1.5, 1500 * time.Millisecond},
+	}
+
+	for _, tc := range cases {
+		d, err := parseConfigDuration(tc.v)
+		if err != nil || time.Duration(d) != tc.want {
+			t.Fatalf("expected %v of %v, got %v %v", tc.want, tc.v, time.Duration(d), err)
+		}
+	}
+
+	for _, v := range []interface{}{"soon", true} {
+		if _, err := parseConfigDuration(v); err == nil {
+			t.Fatalf("expected error of %v", v)
+		}
+	}
+}
@@ -1,11 +1,15 @@
 package noob
 
 import (
+	"errors"
 	"fmt"
+	keyvalue "github.com/alfarih31/nb-go-keyvalue"
 	"github.com/alfarih31/nb-go-parser"
 	"github.com/gin-gonic/gin"
 	"net"
+	"net/http"
 	"os"
+	"sync"
 	"time"
 )
 
@@ -15,6 +19,33 @@ type Ctx struct {
 
 	Listener net.Listener
 	*Router
+
+	// Cfg, CORSCfg, ThrottlingCfg, Meta & Debug are snapshot of the configuration owned by this application.
+	// They are initialized from the package defaults on New
+	Cfg           Cfg
+	CORSCfg       CORSCfg
+	ThrottlingCfg ThrottlingCfg
+	Meta          keyvalue.KeyValue
+	Debug         bool
+
+	servers     []*server
+	listeners   []listenerEntry
+	middlewares []HandlerFunc
+	// accessLog replace the request logger when set
+	accessLog *AccessLogCfg
+	metrics   *Metrics
+	tracing   *TracingCfg
+	// routeOverrides is resolved overrides by method & full path of routes, matcher find route of requests not matched by gin
+	routeOverrides map[string]*routeOverrides
+	matcher        *routeMatcher
+	setupOnce      sync.Once
+	setupErr       error
+	shutdownHooks  []ShutdownHook
+	shutdownOnce   sync.Once
+	shutdownDone   chan struct{}
+	shutdownErr    error
+	shuttingDown   bool
+	mu             sync.Mutex
 }
 
 // Start will run the Core & start serving the application
@@ -26,79 +57,269 @@ func (co *Ctx) Start() error {
 		e error
 	)
 
-	cfg := DefaultCfg
-
-	crs := new(cors)
-
-	// Prepare handlers for no route
-	middlewares := []HandlerFunc{handleRequestLogger(log), crs.HandleCORS, HandleThrottling(), HandleTimeout}
+	if e = co.setup(); e != nil {
+		return e
+	}
 
-	co.USE(middlewares...)
-	// Handle root
-	co.GET("/", HandleAPIStatus)
+	cfg := co.Cfg
 
-	// handler for not found page
-	middlewares = append(middlewares, HandleNotFound)
-	co.Provider.Engine.NoRoute(NewHandlerChain(middlewares).compact())
+	useTLS := cfg.TLS.Enabled()
 
 	hostInfo := cfg.Host
 	if hostInfo == "" {
 		hostInfo = "http://localhost"
+		if useTLS {
+			hostInfo = "https://localhost"
+		}
+	}
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Handler: co.Provider.Engine,
+	}
+
+	if useTLS {
+		if srv.TLSConfig, e = cfg.TLS.Build(); e != nil {
+			return e
+		}
+	}
+
+	servers := []*server{{Server: srv, useTLS: useTLS}}
+
+	if cfg.H2C && !useTLS {
+		if e = servers[0].enableH2C(); e != nil {
+			return e
+		}
 	}
 
 	// use listener if listener not nil
 	if co.Listener != nil && cfg.UseListener {
-		url := fmt.Sprintf("%s%s", co.Listener.Addr().String(), cfg.Path)
-		log.Info(fmt.Sprintf("TimeToBoot = %s Running: Address = '%s'", time.Since(co.startTime).String(), url), map[string]interface{}{
-			"address": url,
-		})
-
-		e = co.Provider.Engine.RunListener(co.Listener)
+		servers[0].listener = co.Listener
+		servers[0].url = fmt.Sprintf("%s%s", co.Listener.Addr().String(), cfg.Path)
 	} else {
 		baseUrlInfo := fmt.Sprintf("%s:%d", hostInfo, cfg.Port)
-		url := fmt.Sprintf("%s%s", baseUrlInfo, cfg.Path)
-		log.Info(fmt.Sprintf("TimeToBoot = %s Running: Url = '%s'", time.Since(co.startTime).String(), url), map[string]interface{}{
-			"url": url,
+		servers[0].url = fmt.Sprintf("%s%s", baseUrlInfo, cfg.Path)
+	}
+
+	// Additional listeners
+	extras, e := co.listenerServers()
+	if e != nil {
+		return e
+	}
+	servers = append(servers, extras...)
+
+	co.mu.Lock()
+	// Shutdown is called before servers are registered, don't serve
+	if co.shuttingDown {
+		co.mu.Unlock()
+		<-co.shutdownDone
+		return co.shutdownErr
+	}
+	co.servers = servers
+	co.mu.Unlock()
+
+	if cfg.HandleSignal {
+		// Stop handling signal once Start returns, so the process can be interrupted again
+		stopSignal := make(chan struct{})
+		defer close(stopSignal)
+
+		go co.handleSignal(stopSignal)
+	}
+
+	errc := make(chan error, len(servers))
+	for _, s := range servers {
+		log.Info(fmt.Sprintf("TimeToBoot = %s Running: Url = '%s'", time.Since(co.startTime).String(), s.url), map[string]interface{}{
+			"url": s.url,
 		})
 
-		e = co.Provider.Run(fmt.Sprintf("%s:%d", cfg.Host, cfg.Port))
+		go func(s *server) {
+			errc <- s.serve()
+		}(s)
+	}
+
+	e = <-errc
+
+	// Server is closed by Shutdown, wait until draining & hooks are done
+	if errors.Is(e, http.ErrServerClosed) {
+		<-co.shutdownDone
+		return co.shutdownErr
 	}
 
+	// One of servers failed, stop the others. ShutdownHook is not executed, as the application is not shut down
+	co.closeServers()
+
 	return e
 }
 
+// Handler boot the application & return it as http.Handler, e.g. to embed it in other net/http server or httptest.Server.
+// Boot is done once, so Handler can be called multiple times & together with Start
+func (co *Ctx) Handler() (http.Handler, error) {
+	if err := co.setup(); err != nil {
+		return nil, err
+	}
+
+	return co.Provider.Engine, nil
+}
+
+// Routes return all routes registered to the application, including those of other routers of Provider
+func (co *Ctx) Routes() []RouteInfo {
+	return co.Provider.Routes()
+}
+
+// handleFallback register handlers of not found & method not allowed requests to engine
+func (co *Ctx) handleFallback(engine *gin.Engine) {
+	chain := func(h HandlerFunc) gin.HandlerFunc {
+		return NewHandlerChain(append(append([]HandlerFunc{}, co.middlewares...), h)).compact()
+	}
+
+	engine.HandleMethodNotAllowed = true
+	engine.NoRoute(chain(HandleNotFound))
+	engine.NoMethod(chain(newMethodNotAllowedHandler(engine)))
+}
+
+// OpenAPI generate OpenAPI 3 document of the application. Info is taken from Meta app_name, app_description & app_version
+func (co *Ctx) OpenAPI() *OpenAPIDocument {
+	info := OpenAPIInfo{}
+	if v, exist := co.Meta["app_name"]; exist {
+		info.Title = fmt.Sprint(v)
+	}
+	if v, exist := co.Meta["app_description"]; exist {
+		info.Description = fmt.Sprint(v)
+	}
+	if v, exist := co.Meta["app_version"]; exist {
+		info.Version = fmt.Sprint(v)
+	}
+
+	return co.Provider.OpenAPI(info)
+}
+
+// setup validate configuration, then register common middlewares, status route & routers once
+func (co *Ctx) setup() error {
+	// Validate configuration before serving
+	if err := co.Config().Validate(); err != nil {
+		return err
+	}
+
+	co.setupOnce.Do(func() {
+		crs := new(cors)
+
+		// Bind application to each request, so handlers read configuration from the owning application
+		co.Provider.Engine.Use(co.bind)
+
+		requestLogger := handleRequestLogger(log)
+		if co.accessLog != nil {
+			var errs Errors
+			if requestLogger, errs = newAccessLogHandler(*co.accessLog); errs != nil {
+				co.setupErr = errs
+				return
+			}
+		}
+
+		// Common middlewares, also applied to not found & method not allowed requests
+		co.middlewares = []HandlerFunc{HandleRequestID, requestLogger}
+		if co.tracing != nil {
+			co.middlewares = append([]HandlerFunc{HandleTracing(*co.tracing)}, co.middlewares...)
+		}
+		if co.metrics != nil {
+			co.middlewares = append(co.middlewares, co.metrics.Handle)
+		}
+		co.middlewares = append(co.middlewares, crs.HandleCORS, newThrottlingHandler(co.ThrottlingCfg), HandleTimeout)
+
+		co.USE(co.middlewares...)
+		// Handle root
+		co.GET("/", HandleAPIStatus).Doc(RouteDoc{
+			Summary:   "API status",
+			Responses: map[HTTPStatusCode]interface{}{StatusOK: keyvalue.KeyValue{}},
+		})
+
+		// Resolve route overrides, so built-in middlewares can find them by the matched route
+		var errs Errors
+		co.routeOverrides = map[string]*routeOverrides{}
+		for _, r := range co.Provider.Routes() {
+			if r.overrides != nil {
+				errs = append(errs, r.overrides.validate(r.Method+" "+r.Path)...)
+				co.routeOverrides[r.Method+" "+r.Path] = r.overrides
+			}
+		}
+
+		if len(errs) > 0 {
+			co.setupErr = errs
+			return
+		}
+
+		co.matcher = newRouteMatcher(co.Provider.Engine)
+		co.handleFallback(co.Provider.Engine)
+
+		co.setupErr = co.Provider.preRun()
+	})
+
+	return co.setupErr
+}
+
 func notImplemented(fname string) func() error {
 	return func() error {
 		panic(NewCoreError(fmt.Sprintf("Core.%s not implemented", fname)))
-
-		return nil
 	}
 }
 
-// New return Core context, used as core of the application
-func New(listener ...net.Listener) *Ctx {
-	// Load isDebug
-	isDebug, _ = parser.String(os.Getenv("DEBUG")).ToBool()
-
-	if !isDebug {
-		gin.SetMode(gin.ReleaseMode)
+// Config return snapshot of the application configuration
+func (co *Ctx) Config() Config {
+	return Config{
+		Cfg:           co.Cfg,
+		CORSCfg:       co.CORSCfg.copy(),
+		ThrottlingCfg: co.ThrottlingCfg,
+		Meta:          copyKeyValue(co.Meta),
 	}
+}
 
-	p := HTTP()
+// isDebugEnv load debug mode from DEBUG env
+func isDebugEnv() bool {
+	debug, _ := parser.String(os.Getenv("DEBUG")).ToBool()
 
-	r := p.Router(DefaultCfg.Path)
+	return debug
+}
+
+// bind store the application to request context
+func (co *Ctx) bind(c *gin.Context) {
+	c.Set(extKeyApp, co)
+}
 
-	var lis net.Listener
+// New return Core context, used as core of the application. Use NewWithOptions to configure the application
+func New(listener ...net.Listener) *Ctx {
+	var opts []Option
 	if len(listener) > 0 {
-		lis = listener[0]
+		opts = append(opts, WithListener(listener[0]))
 	}
 
+	return NewWithOptions(opts...)
+}
+
+// NewWithOptions return Core context configured by opts.
+// Configuration is snapshot from DefaultCfg, DefaultCORSCfg, DefaultThrottlingCfg, DefaultMeta & DEBUG env, then overridden by opts
+func NewWithOptions(opts ...Option) *Ctx {
 	c := &Ctx{
-		startTime: time.Now(),
-		Provider:  p,
-		Listener:  lis,
-		Router:    r,
+		startTime:     time.Now(),
+		Cfg:           DefaultCfg,
+		CORSCfg:       DefaultCORSCfg.copy(),
+		ThrottlingCfg: DefaultThrottlingCfg,
+		Meta:          copyKeyValue(DefaultMeta),
+		Debug:         isDebugEnv(),
+		shutdownDone:  make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
 
+	// Gin mode is process-wide, so it follows DEBUG env instead of Debug of each application
+	if !isDebugEnv() && gin.Mode() != gin.ReleaseMode {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	p := HTTP()
+
+	c.Provider = p
+	c.Router = p.Router(c.Cfg.Path)
+
 	return c
 }
@@ -21,6 +21,9 @@ type CoreError struct {
 	Meta   interface{}               `json:"meta"`
 	Stack  []*gostackparse.Goroutine `json:"_stacks,omitempty"`
 	Frames *runtime.Frames           `json:"_frames,omitempty"`
+
+	// RequestID is ID of the request the error occurred, empty if it is not occurred in a request
+	RequestID string `json:"request_id,omitempty"`
 }
 
 func GetRuntimeFrames(skip int) *runtime.Frames {
@@ -107,6 +110,10 @@ func (msg *CoreError) JSON() interface{} {
 
 	jsonData["_stack"] = msg.Stack
 
+	if msg.RequestID != "" {
+		jsonData["request_id"] = msg.RequestID
+	}
+
 	return jsonData
 }
 
@@ -125,6 +132,16 @@ func (e Errors) MarshalJSON() ([]byte, error) {
 	return json.Marshal(jsonData)
 }
 
+// Error return messages of all errors, so Errors can be returned as error
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, er := range e {
+		msgs[i] = er.Error()
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
 func (e Errors) String() string {
 	if len(e) == 0 {
 		return ""
@@ -0,0 +1,141 @@
+package noob
+
+import (
+	"fmt"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"net"
+	"net/http"
+)
+
+// listenerEntry is additional listener & routers served on it
+type listenerEntry struct {
+	listener net.Listener
+	routers  []*Router
+	tls      *TLSCfg
+}
+
+// server is http.Server served by Ctx
+type server struct {
+	*http.Server
+	listener net.Listener
+	useTLS   bool
+	url      string
+}
+
+func (s *server) serve() error {
+	if s.listener == nil {
+		if s.useTLS {
+			return s.ListenAndServeTLS("", "")
+		}
+
+		return s.ListenAndServe()
+	}
+
+	if s.useTLS {
+		return s.ServeTLS(s.listener, "", "")
+	}
+
+	return s.Serve(s.listener)
+}
+
+// enableH2C serve HTTP/2 cleartext, both prior knowledge & HTTP/1.1 Upgrade, with fallback to HTTP/1.1.
+// It must only be used on server without TLS, TLS servers negotiate HTTP/2 with ALPN
+func (s *server) enableH2C() error {
+	h2s := &http2.Server{}
+
+	// Register h2s to the server, so HTTP/2 connections are drained on Shutdown
+	if err := http2.ConfigureServer(s.Server, h2s); err != nil {
+		return NewCoreError(fmt.Sprintf("h2c: configure server error, %v", err))
+	}
+
+	s.Handler = h2c.NewHandler(s.Handler, h2s)
+
+	return nil
+}
+
+// AddListener serve the application over plain HTTP on additional listener, e.g. Unix domain socket or internal admin port, simultaneously with the main server.
+// If routers is set, only handlers of those routers & their branches are served on the listener, while middlewares of their parents are still applied.
+// HTTP/2 cleartext is served when Cfg.H2C is true
+func (co *Ctx) AddListener(listener net.Listener, routers ...*Router) {
+	co.addListener(listenerEntry{
+		listener: listener,
+		routers:  routers,
+	})
+}
+
+// AddTLSListener is AddListener serving HTTPS configured by tlsCfg, e.g. pass Cfg.TLS to use the main server configuration
+func (co *Ctx) AddTLSListener(listener net.Listener, tlsCfg TLSCfg, routers ...*Router) {
+	co.addListener(listenerEntry{
+		listener: listener,
+		routers:  routers,
+		tls:      &tlsCfg,
+	})
+}
+
+func (co *Ctx) addListener(l listenerEntry) {
+	co.mu.Lock()
+	defer co.mu.Unlock()
+
+	co.listeners = append(co.listeners, l)
+}
+
+// listenerServers create server for each additional listener
+func (co *Ctx) listenerServers() ([]*server, error) {
+	co.mu.Lock()
+	listeners := co.listeners
+	co.mu.Unlock()
+
+	servers := make([]*server, len(listeners))
+	for i, l := range listeners {
+		// Derived engine carry settings & middlewares of Provider.Engine, including binding to this application
+		engine := co.Provider.deriveEngine()
+		if err := co.Provider.boot(engine, l.routers...); err != nil {
+			return nil, err
+		}
+		co.handleFallback(engine)
+
+		s := &server{
+			Server: &http.Server{
+				Handler: engine,
+			},
+			listener: l.listener,
+			url:      l.listener.Addr().Network() + "://" + l.listener.Addr().String(),
+		}
+
+		if l.tls != nil {
+			if errs := l.tls.validate(); len(errs) > 0 {
+				return nil, errs
+			}
+
+			tc, err := l.tls.Build()
+			if err != nil {
+				return nil, err
+			}
+
+			s.TLSConfig = tc
+			s.useTLS = true
+		} else if co.Cfg.H2C {
+			if err := s.enableH2C(); err != nil {
+				return nil, err
+			}
+		}
+
+		servers[i] = s
+	}
+
+	return servers, nil
+}
+
+// closeServers close all servers immediately without draining & executing ShutdownHook
+func (co *Ctx) closeServers() {
+	co.mu.Lock()
+	servers := co.servers
+	co.mu.Unlock()
+
+	for _, s := range servers {
+		if err := s.Close(); err != nil {
+			log.Error(err)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package noob
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/http2"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func getStatus(t *testing.T, cl *http.Client, url string) int {
+	t.Helper()
+
+	res, err := cl.Get(url)
+	if err != nil {
+		t.Fatalf("request %s error: %v", url, err)
+	}
+	res.Body.Close()
+
+	return res.StatusCode
+}
+
+func unixClient(path string) *http.Client {
+	return &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		},
+	}}
+}
+
+func TestProviderRunListenerMountRouters(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+
+	p := HTTP()
+	p.Router("/v1").GET("/ping", func(c *HandlerCtx) (Response, error) {
+		return DefaultSuccessResponse, nil
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = p.RunListener(lis)
+	}()
+	defer func() {
+		lis.Close()
+		<-done
+	}()
+
+	url := "http://" + lis.Addr().String() + "/v1/ping"
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		res, err := http.Get(url)
+		if err == nil {
+			res.Body.Close()
+			if res.StatusCode != http.StatusOK {
+				t.Fatalf("expected 200, got %d", res.StatusCode)
+			}
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("request error: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStartUseListenerMountRouters(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.Branch("/v1").GET("/ping", func(c *HandlerCtx) (Response, error) {
+		return DefaultSuccessResponse, nil
+	})
+	app.start(t)
+	defer app.stop(t)
+
+	if s := getStatus(t, http.DefaultClient, app.url("/v1/ping")); s != http.StatusOK {
+		t.Fatalf("expected 200, got %d", s)
+	}
+}
+
+func TestAddListenerRestrictBranches(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "admin.sock")
+	unix, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+
+	app := newTestApp(t, nil)
+
+	// Global middleware on Provider.Engine must be carried to additional listeners
+	app.Provider.Engine.Use(func(c *gin.Context) {
+		c.Header("X-Engine", "1")
+	})
+
+	ok := func(c *HandlerCtx) (Response, error) {
+		return DefaultSuccessResponse, nil
+	}
+	app.Branch("/public").GET("/a", ok)
+	admin := app.Branch("/admin")
+	admin.GET("/b", ok)
+	admin.Branch("/deep").GET("/c", ok)
+
+	app.AddListener(unix, admin)
+	app.start(t)
+	defer app.stop(t)
+
+	cases := []struct {
+		cl     *http.Client
+		url    string
+		status int
+	}{
+		{http.DefaultClient, app.url("/public/a"), http.StatusOK},
+		{http.DefaultClient, app.url("/admin/b"), http.StatusOK},
+		{unixClient(sock), "http://admin/admin/b", http.StatusOK},
+		{unixClient(sock), "http://admin/admin/deep/c", http.StatusOK},
+		{unixClient(sock), "http://admin/public/a", http.StatusNotFound},
+		{unixClient(sock), "http://admin/", http.StatusNotFound},
+	}
+
+	for _, c := range cases {
+		if s := getStatus(t, c.cl, c.url); s != c.status {
+			t.Errorf("%s: expected %d, got %d", c.url, c.status, s)
+		}
+	}
+
+	res, err := unixClient(sock).Get("http://admin/admin/b")
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	res.Body.Close()
+	if res.Header.Get("X-Engine") != "1" {
+		t.Fatal("expected Provider.Engine middleware on additional listener")
+	}
+}
+
+func TestAddListenerTLSPerListener(t *testing.T) {
+	ca := newTestCert(t, "ca", nil)
+	server := newTestCert(t, "server", ca)
+	tlsCfg := TLSCfg{Config: &tls.Config{Certificates: []tls.Certificate{server.tlsCert}}}
+
+	plain, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+
+	secure, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+
+	app := newTestApp(t, func(cfg *Cfg) {
+		cfg.TLS = tlsCfg
+	})
+	app.AddListener(plain)
+	app.AddTLSListener(secure, tlsCfg)
+	app.start(t)
+	defer app.stop(t)
+
+	tlsClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: ca.pool()}}}
+
+	if s := getStatus(t, tlsClient, "https://"+app.addr+"/"); s != http.StatusOK {
+		t.Errorf("main: expected 200, got %d", s)
+	}
+
+	if s := getStatus(t, http.DefaultClient, "http://"+plain.Addr().String()+"/"); s != http.StatusOK {
+		t.Errorf("plain: expected 200, got %d", s)
+	}
+
+	if s := getStatus(t, tlsClient, "https://"+secure.Addr().String()+"/"); s != http.StatusOK {
+		t.Errorf("secure: expected 200, got %d", s)
+	}
+}
+
+func TestStartListenerFailureSkipHooks(t *testing.T) {
+	broken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	broken.Close()
+
+	app := newTestApp(t, nil)
+	app.AddListener(broken)
+
+	hooked := false
+	app.OnShutdown(func(ctx context.Context) error {
+		hooked = true
+		return nil
+	})
+
+	select {
+	case err := <-func() chan error {
+		go func() {
+			app.done <- app.Start()
+		}()
+		return app.done
+	}():
+		if err == nil {
+			t.Fatal("expected error of failed listener")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected Start to return when a listener failed")
+	}
+
+	if hooked {
+		t.Fatal("expected ShutdownHook not to be executed on listener failure")
+	}
+}
+
+// h2cClient return client speaking HTTP/2 cleartext with prior knowledge
+func h2cClient() *http.Client {
+	return &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+}
+
+func TestStartH2CPriorKnowledge(t *testing.T) {
+	app := newTestApp(t, func(cfg *Cfg) {
+		cfg.H2C = true
+	})
+	app.start(t)
+	defer app.stop(t)
+
+	res, err := h2cClient().Get(app.url("/"))
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK || res.ProtoMajor != 2 {
+		t.Fatalf("expected 200 over HTTP/2, got %d %s", res.StatusCode, res.Proto)
+	}
+
+	// Not found over HTTP/2 is answered with the JSON envelope, same as HTTP/1.1
+	res, err = h2cClient().Get(app.url("/missing"))
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	defer res.Body.Close()
+
+	var body ResponseBody
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNotFound || res.ProtoMajor != 2 || body.Message != "not found" {
+		t.Fatalf("expected 404 envelope over HTTP/2, got %d %s %+v", res.StatusCode, res.Proto, body)
+	}
+}
+
+func TestStartH2CUpgrade(t *testing.T) {
+	app := newTestApp(t, func(cfg *Cfg) {
+		cfg.H2C = true
+	})
+	app.start(t)
+	defer app.stop(t)
+
+	conn, err := net.Dial("tcp", app.addr)
+	if err != nil {
+		t.Fatalf("dial error: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	// HTTP2-Settings is SETTINGS_MAX_CONCURRENT_STREAMS = 100 & SETTINGS_INITIAL_WINDOW_SIZE = 65535
+	fmt.Fprintf(conn, "GET / HTTP/1.1\r\nHost: %s\r\nConnection: Upgrade, HTTP2-Settings\r\nUpgrade: h2c\r\nHTTP2-Settings: AAMAAABkAAQAAP__\r\n\r\n", app.addr)
+
+	res, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatalf("read response error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusSwitchingProtocols || res.Header.Get("Upgrade") != "h2c" {
+		t.Fatalf("expected upgrade to h2c, got %d %v", res.StatusCode, res.Header)
+	}
+}
+
+func TestStartH2CFallbackHTTP1(t *testing.T) {
+	app := newTestApp(t, func(cfg *Cfg) {
+		cfg.H2C = true
+	})
+	app.start(t)
+	defer app.stop(t)
+
+	res, err := http.Get(app.url("/"))
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK || res.ProtoMajor != 1 {
+		t.Fatalf("expected 200 over HTTP/1.1, got %d %s", res.StatusCode, res.Proto)
+	}
+}
+
+func TestStartWithoutH2C(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.start(t)
+	defer app.stop(t)
+
+	if _, err := h2cClient().Get(app.url("/")); err == nil {
+		t.Fatal("expected prior knowledge HTTP/2 request to fail when h2c is disabled")
+	}
+}
+
+func TestAddListenerH2C(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+
+	app := newTestApp(t, func(cfg *Cfg) {
+		cfg.H2C = true
+	})
+	app.AddListener(lis)
+	app.start(t)
+	defer app.stop(t)
+
+	res, err := h2cClient().Get("http://" + lis.Addr().String() + "/")
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK || res.ProtoMajor != 2 {
+		t.Fatalf("expected 200 over HTTP/2, got %d %s", res.StatusCode, res.Proto)
+	}
+}
@@ -0,0 +1,86 @@
+package noob
+
+import (
+	keyvalue "github.com/alfarih31/nb-go-keyvalue"
+	"net"
+)
+
+// Option is functional option to configure Ctx on NewWithOptions
+type Option func(co *Ctx)
+
+// WithCfg set the server configuration of the application, replacing DefaultCfg
+func WithCfg(cfg Cfg) Option {
+	return func(co *Ctx) {
+		co.Cfg = cfg
+	}
+}
+
+// WithCORSCfg set the CORS configuration of the application, replacing DefaultCORSCfg
+func WithCORSCfg(cfg CORSCfg) Option {
+	return func(co *Ctx) {
+		co.CORSCfg = cfg.copy()
+	}
+}
+
+// WithThrottlingCfg set the throttling configuration of the application, replacing DefaultThrottlingCfg
+func WithThrottlingCfg(cfg ThrottlingCfg) Option {
+	return func(co *Ctx) {
+		co.ThrottlingCfg = cfg
+	}
+}
+
+// WithMeta set the meta shown on API status, replacing DefaultMeta
+func WithMeta(meta keyvalue.KeyValue) Option {
+	return func(co *Ctx) {
+		co.Meta = copyKeyValue(meta)
+	}
+}
+
+// WithDebug set debug mode of the application, replacing DEBUG env
+func WithDebug(debug bool) Option {
+	return func(co *Ctx) {
+		co.Debug = debug
+	}
+}
+
+// WithAccessLog replace the request logger of the application with access log configured by cfg
+func WithAccessLog(cfg AccessLogCfg) Option {
+	return func(co *Ctx) {
+		co.accessLog = &cfg
+	}
+}
+
+// WithMetrics record metrics of every request of the application, including not found & method not allowed, to m.
+// Use MountMetrics to expose them
+func WithMetrics(m *Metrics) Option {
+	return func(co *Ctx) {
+		co.metrics = m
+	}
+}
+
+// WithTracing trace every request of the application, including not found & method not allowed, configured by cfg
+func WithTracing(cfg TracingCfg) Option {
+	return func(co *Ctx) {
+		co.tracing = &cfg
+	}
+}
+
+// WithListener set listener used when Cfg.UseListener is true
+func WithListener(listener net.Listener) Option {
+	return func(co *Ctx) {
+		co.Listener = listener
+	}
+}
+
+func copyKeyValue(kv keyvalue.KeyValue) keyvalue.KeyValue {
+	if kv == nil {
+		return nil
+	}
+
+	c := keyvalue.KeyValue{}
+	for k, v := range kv {
+		c[k] = v
+	}
+
+	return c
+}
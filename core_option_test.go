@@ -0,0 +1,95 @@
+package noob
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"testing"
+
+	keyvalue "github.com/alfarih31/nb-go-keyvalue"
+)
+
+func getAPIStatus(t *testing.T, url string, origin string) (*http.Response, map[string]interface{}) {
+	t.Helper()
+
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	defer res.Body.Close()
+
+	var body ResponseBody
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	data, _ := body.Data.(map[string]interface{})
+	return res, data
+}
+
+func TestNewWithOptionsIsolateApplications(t *testing.T) {
+	public := newTestApp(t, nil,
+		WithMeta(keyvalue.KeyValue{"app_name": "public"}),
+		WithCORSCfg(CORSCfg{Enable: true, AllowOrigins: []string{"http://public.test"}}),
+	)
+	admin := newTestApp(t, nil,
+		WithMeta(keyvalue.KeyValue{"app_name": "admin"}),
+		WithCORSCfg(CORSCfg{Enable: true, AllowOrigins: []string{"http://admin.test"}}),
+	)
+
+	public.start(t)
+	defer public.stop(t)
+	admin.start(t)
+	defer admin.stop(t)
+
+	_, data := getAPIStatus(t, public.url("/"), "")
+	if data["app_name"] != "public" {
+		t.Fatalf("expected public meta, got %v", data)
+	}
+
+	_, data = getAPIStatus(t, admin.url("/"), "")
+	if data["app_name"] != "admin" {
+		t.Fatalf("expected admin meta, got %v", data)
+	}
+
+	res, _ := getAPIStatus(t, public.url("/"), "http://admin.test")
+	if res.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected public to reject admin origin, got %d", res.StatusCode)
+	}
+
+	res, _ = getAPIStatus(t, admin.url("/"), "http://admin.test")
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected admin to allow admin origin, got %d", res.StatusCode)
+	}
+}
+
+func TestNewSnapshotDefaults(t *testing.T) {
+	app := NewWithOptions()
+
+	DefaultMeta["app_name"] = "changed"
+	defer func() {
+		DefaultMeta["app_name"] = "Core"
+	}()
+
+	if app.Meta["app_name"] == "changed" {
+		t.Fatal("expected Meta to be snapshot on NewWithOptions")
+	}
+}
+
+func TestNewWithListener(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+	defer lis.Close()
+
+	app := New(lis)
+	if app.Listener != lis {
+		t.Fatal("expected listener to be kept by New")
+	}
+}
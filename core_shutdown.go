@@ -0,0 +1,94 @@
+package noob
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ShutdownHook is a cleanup function executed by Ctx.Shutdown after the server stop serving, e.g. closing DB connection or flushing logs
+type ShutdownHook func(ctx context.Context) error
+
+// OnShutdown register hooks to be executed on Shutdown. Hooks are executed in reverse registration order
+func (co *Ctx) OnShutdown(hooks ...ShutdownHook) {
+	co.mu.Lock()
+	defer co.mu.Unlock()
+
+	co.shutdownHooks = append(co.shutdownHooks, hooks...)
+}
+
+// Shutdown stop accepting new connections, wait for active requests to be drained & execute registered ShutdownHook.
+// If ctx has no deadline, Cfg.ShutdownTimeout is used as the drain timeout. Shutdown is only executed once, next calls return the first result.
+// If Shutdown is called before Start serves, Start return without serving
+func (co *Ctx) Shutdown(ctx context.Context) error {
+	co.shutdownOnce.Do(func() {
+		defer close(co.shutdownDone)
+
+		if _, ok := ctx.Deadline(); !ok && co.Cfg.ShutdownTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, co.Cfg.ShutdownTimeout)
+			defer cancel()
+		}
+
+		co.mu.Lock()
+		co.shuttingDown = true
+		servers := co.servers
+		hooks := make([]ShutdownHook, len(co.shutdownHooks))
+		copy(hooks, co.shutdownHooks)
+		co.mu.Unlock()
+
+		var errs Errors
+		if len(servers) > 0 {
+			log.Info("shutting down, draining active requests")
+		}
+
+		for _, srv := range servers {
+			if err := srv.Shutdown(ctx); err != nil {
+				errs = append(errs, NewCoreError(fmt.Sprintf("drain requests on %s error, %v", srv.url, err)))
+			}
+		}
+
+		// Execute hooks in reverse order
+		for i := len(hooks) - 1; i >= 0; i-- {
+			if err := hooks[i](ctx); err != nil {
+				errs = append(errs, NewCoreError(fmt.Sprintf("shutdown hook error, %v", err)))
+			}
+		}
+
+		if len(errs) > 0 {
+			log.Error("shutdown finished with errors", map[string]interface{}{"_error": errs.String()})
+			co.shutdownErr = NewCoreError("shutdown error", errs)
+			return
+		}
+
+		log.Info("shutdown finished")
+	})
+
+	<-co.shutdownDone
+
+	return co.shutdownErr
+}
+
+// handleSignal wait for SIGINT or SIGTERM then Shutdown the application, until stop is closed
+func (co *Ctx) handleSignal(stop <-chan struct{}) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sig)
+
+	select {
+	case s := <-sig:
+		log.Info(fmt.Sprintf("received signal %s", s), map[string]interface{}{
+			"signal": s.String(),
+		})
+	case <-co.shutdownDone:
+		return
+	case <-stop:
+		return
+	}
+
+	if err := co.Shutdown(context.Background()); err != nil {
+		log.Error(err)
+	}
+}
@@ -0,0 +1,158 @@
+package noob
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShutdownDrainActiveRequest(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	started := make(chan struct{})
+	app.GET("/slow", func(c *HandlerCtx) (Response, error) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		return DefaultSuccessResponse, nil
+	})
+	app.start(t)
+
+	status := make(chan int, 1)
+	go func() {
+		res, err := http.Get(app.url("/slow"))
+		if err != nil {
+			status <- 0
+			return
+		}
+		res.Body.Close()
+		status <- res.StatusCode
+	}()
+
+	<-started
+	if err := app.stop(t); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if s := <-status; s != http.StatusOK {
+		t.Fatalf("expected in-flight request to be drained with 200, got %d", s)
+	}
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	app := newTestApp(t, func(cfg *Cfg) {
+		cfg.ShutdownTimeout = 50 * time.Millisecond
+	})
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	app.GET("/stuck", func(c *HandlerCtx) (Response, error) {
+		close(started)
+		<-release
+		return DefaultSuccessResponse, nil
+	})
+	app.start(t)
+
+	go func() {
+		res, err := http.Get(app.url("/stuck"))
+		if err == nil {
+			res.Body.Close()
+		}
+	}()
+
+	<-started
+	begin := time.Now()
+	err := app.Shutdown(context.Background())
+	if err == nil {
+		t.Fatal("expected drain timeout error")
+	}
+
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Fatalf("expected shutdown to stop after timeout, took %s", elapsed)
+	}
+
+	if startErr := <-app.done; startErr != err {
+		t.Fatalf("expected Start to return shutdown error, got %v", startErr)
+	}
+}
+
+func TestShutdownHooksReverseOrder(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	var (
+		mu    sync.Mutex
+		order []int
+	)
+	hook := func(i int) ShutdownHook {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			defer mu.Unlock()
+			order = append(order, i)
+			return nil
+		}
+	}
+
+	app.OnShutdown(hook(1), hook(2))
+	app.OnShutdown(hook(3))
+	app.start(t)
+
+	if err := app.stop(t); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	if !reflect.DeepEqual(order, []int{3, 2, 1}) {
+		t.Fatalf("expected hooks in reverse order, got %v", order)
+	}
+}
+
+func TestShutdownOnce(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	calls := 0
+	hookErr := errors.New("close db error")
+	app.OnShutdown(func(ctx context.Context) error {
+		calls++
+		return hookErr
+	})
+	app.start(t)
+
+	err1 := app.Shutdown(context.Background())
+	err2 := app.Shutdown(context.Background())
+
+	if calls != 1 {
+		t.Fatalf("expected hook to be executed once, got %d", calls)
+	}
+
+	if err1 == nil || err1 != err2 {
+		t.Fatalf("expected same error on each call, got %v & %v", err1, err2)
+	}
+
+	if err := <-app.done; err != err1 {
+		t.Fatalf("expected Start to return shutdown error, got %v", err)
+	}
+}
+
+func TestShutdownBeforeStart(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	if err := app.Shutdown(context.Background()); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	go func() {
+		app.done <- app.Start()
+	}()
+
+	select {
+	case err := <-app.done:
+		if err != nil {
+			t.Fatalf("expected nil error, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected Start to return when already shut down")
+	}
+}
@@ -0,0 +1,161 @@
+package noob
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// testApp is application served on a random local port
+type testApp struct {
+	*Ctx
+	addr string
+	done chan error
+}
+
+// newTestApp create application listening on a random local port. configure is called before routes are registered
+func newTestApp(t *testing.T, configure func(cfg *Cfg), opts ...Option) *testApp {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+
+	cfg := DefaultCfg
+	cfg.UseListener = true
+	if configure != nil {
+		configure(&cfg)
+	}
+
+	app := NewWithOptions(append([]Option{WithListener(lis), WithCfg(cfg)}, opts...)...)
+
+	return &testApp{
+		Ctx:  app,
+		addr: lis.Addr().String(),
+		done: make(chan error, 1),
+	}
+}
+
+// start serve the application & wait until it serve
+func (a *testApp) start(t *testing.T) {
+	t.Helper()
+
+	go func() {
+		a.done <- a.Start()
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		// Served routes are booted before serving, so wait until Start register its servers
+		a.mu.Lock()
+		serving := len(a.servers) > 0
+		a.mu.Unlock()
+
+		if serving {
+			return
+		}
+
+		select {
+		case err := <-a.done:
+			t.Fatalf("start error: %v", err)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	t.Fatal("application is not serving")
+}
+
+// stop shutdown the application & return result of Start
+func (a *testApp) stop(t *testing.T) error {
+	t.Helper()
+
+	_ = a.Shutdown(context.Background())
+
+	select {
+	case err := <-a.done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("start is not returning after shutdown")
+	}
+
+	return nil
+}
+
+func (a *testApp) url(path string) string {
+	return "http://" + a.addr + path
+}
+
+func TestStartInvalidConfig(t *testing.T) {
+	app := newTestApp(t, func(cfg *Cfg) {
+		cfg.Port = -1
+	})
+
+	err := app.Start()
+
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("expected 1 config error, got %v", err)
+	}
+}
+
+func TestHandlerBootOnce(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.GET("/ping", func(c *HandlerCtx) (Response, error) {
+		return DefaultSuccessResponse, nil
+	})
+
+	h1, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	// Calling again must not register the status route & middlewares twice
+	h2, err := app.Handler()
+	if err != nil || h1 != h2 {
+		t.Fatalf("expected same handler, got %v", err)
+	}
+
+	srv := httptest.NewServer(h1)
+	defer srv.Close()
+
+	for path, code := range map[string]int{"/": http.StatusOK, "/ping": http.StatusOK, "/missing": http.StatusNotFound} {
+		res, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("request error: %v", err)
+		}
+		res.Body.Close()
+
+		if res.StatusCode != code {
+			t.Fatalf("expected %d for %s, got %d", code, path, res.StatusCode)
+		}
+	}
+
+	// Start after Handler reuse the booted engine
+	app.start(t)
+	defer app.stop(t)
+
+	res, err := http.Get(app.url("/ping"))
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", res.StatusCode)
+	}
+}
+
+func TestHandlerInvalidConfig(t *testing.T) {
+	app := newTestApp(t, func(cfg *Cfg) {
+		cfg.Port = -1
+	})
+
+	if h, err := app.Handler(); err == nil || h != nil {
+		t.Fatalf("expected config error, got %v", err)
+	}
+}
@@ -6,8 +6,6 @@ import (
 	"time"
 )
 
-var isDebug = false
-
 var DefaultMeta = keyvalue.KeyValue{
 	"app_name":        "Core",
 	"app_description": "Core API",
@@ -33,10 +31,24 @@ type CORSCfg struct {
 	MaxAge           time.Duration
 }
 
+func (c CORSCfg) copy() CORSCfg {
+	if c.AllowOrigins != nil {
+		origins := make([]string, len(c.AllowOrigins))
+		copy(origins, c.AllowOrigins)
+		c.AllowOrigins = origins
+	}
+
+	return c
+}
+
 type ThrottlingCfg struct {
 	Enable         bool
 	MaxEventPerSec int
 	MaxBurstSize   int
+
+	// Store keep the throttling state instead of in-memory limiter, e.g. NewRedisRateLimitStore to share it between instances.
+	// Request is allowed when Store fail
+	Store RateLimitStore
 }
 
 type Cfg struct {
@@ -45,6 +57,21 @@ type Cfg struct {
 	Path           string
 	RequestTimeout time.Duration
 	UseListener    bool
+
+	// ShutdownTimeout is max duration to wait active requests to be drained on Shutdown, 0 means wait indefinitely
+	ShutdownTimeout time.Duration
+
+	// HandleSignal enable Shutdown on SIGINT or SIGTERM
+	HandleSignal bool
+
+	// TLS enable HTTPS when configured
+	TLS TLSCfg
+
+	// H2C enable HTTP/2 cleartext (h2c) when TLS is not enabled, both by upgrade & prior knowledge
+	H2C bool
+
+	// MaxBodySize is max size in bytes of request body read by HandlerCtx.Bind, 0 means DefaultMaxBodySize
+	MaxBodySize int
 }
 
 var DefaultCORSCfg = CORSCfg{
@@ -53,7 +80,7 @@ var DefaultCORSCfg = CORSCfg{
 	AllowHeaders:     "*",
 	AllowMethods:     "GET,POST,PUT,DELETE,PATCH,OPTIONS",
 	AllowCredentials: true,
-	ExposeHeaders:    "authorization,content-type",
+	ExposeHeaders:    "authorization,content-type,x-request-id",
 	MaxAge:           time.Duration(0),
 }
 
@@ -64,11 +91,13 @@ var DefaultThrottlingCfg = ThrottlingCfg{
 }
 
 var DefaultCfg = Cfg{
-	Host:           "",
-	Port:           8080,
-	Path:           "/",
-	RequestTimeout: 0,
-	UseListener:    false,
+	Host:            "",
+	Port:            8080,
+	Path:            "/",
+	RequestTimeout:  0,
+	UseListener:     false,
+	ShutdownTimeout: 10 * time.Second,
+	HandleSignal:    false,
 }
 
 const (
@@ -78,6 +107,11 @@ const (
 	statusCodeErrTooManyRequest
 	statusCodeErrRequestTimeout
 	statusCodeErrForbidden
+	statusCodeErrMethodNotAllowed
+	statusCodeErrBadRequest
+	statusCodeErrRequestEntityTooLarge
+	statusCodeErrUnsupportedMediaType
+	statusCodeErrUnprocessableEntity
 )
 
 var DefaultSuccessResponse = NewResponse(StatusOK, ResponseBody{
@@ -102,6 +136,11 @@ var DefaultNotFoundErrorResponse = NewResponseError(StatusNotFound, ResponseBody
 	Message: "not found",
 })
 
+var DefaultMethodNotAllowedErrorResponse = NewResponseError(StatusMethodNotAllowed, ResponseBody{
+	Code:    statusCodeErrMethodNotAllowed,
+	Message: "method not allowed",
+})
+
 var DefaultTooManyRequestsErrorResponse = NewResponseError(StatusTooManyRequests, ResponseBody{
 	Code:    statusCodeErrTooManyRequest,
 	Message: "too many request",
@@ -111,3 +150,23 @@ var DefaultRequestTimeoutErrorResponse = NewResponseError(StatusRequestTimeout,
 	Code:    statusCodeErrRequestTimeout,
 	Message: "request timed out",
 })
+
+var DefaultBadRequestErrorResponse = NewResponseError(StatusBadRequest, ResponseBody{
+	Code:    statusCodeErrBadRequest,
+	Message: "bad request",
+})
+
+var DefaultRequestEntityTooLargeErrorResponse = NewResponseError(StatusRequestEntityTooLarge, ResponseBody{
+	Code:    statusCodeErrRequestEntityTooLarge,
+	Message: "request entity too large",
+})
+
+var DefaultUnsupportedMediaTypeErrorResponse = NewResponseError(StatusUnsupportedMediaType, ResponseBody{
+	Code:    statusCodeErrUnsupportedMediaType,
+	Message: "unsupported media type",
+})
+
+var DefaultUnprocessableEntityErrorResponse = NewResponseError(StatusUnprocessableEntity, ResponseBody{
+	Code:    statusCodeErrUnprocessableEntity,
+	Message: "unprocessable entity",
+})
@@ -61,39 +61,37 @@ func (b ResponseBody) String() string {
 }
 
 func main() {
-	env, _ := _env.LoadEnv(".env", true)
+	_, _ = _env.LoadEnv(".env", true)
 
 	rl := logger.New("RootLogger")
 
-	basePath, _ := env.GetString("SERVER_PATH", "/v1")
-	baseHost, _ := env.GetString("SERVER_HOST", "")
-	basePort, _ := env.GetInt("SERVER_PORT", 8080)
-
 	noob.TCFunc(noob.Func{
 		Try: func() {
-			noob.DefaultMeta = keyvalue.KeyValue{
-				"app_name":        "test",
-				"app_version":     "v0.1.0",
-				"app_description": "Description",
-			}
-
-			noob.DefaultCfg = noob.Cfg{
-				Host: baseHost,
-				Path: basePath,
-				Port: basePort,
-			}
-
-			noob.DefaultThrottlingCfg = noob.ThrottlingCfg{
-				MaxEventPerSec: 2,
-				MaxBurstSize:   1,
-			}
-
-			noob.DefaultCORSCfg = noob.CORSCfg{
-				Enable:       true,
-				AllowOrigins: []string{"*"},
+			// Load SERVER_HOST, SERVER_PORT, SERVER_PATH, etc. from env & optional config file
+			cfg, err := noob.LoadConfig(noob.LoadConfigOptions{
+				Files:              []string{"config.yaml"},
+				IgnoreMissingFiles: true,
+			})
+			if err != nil {
+				panic(err)
 			}
 
-			app := noob.New()
+			app := noob.NewWithOptions(
+				noob.WithConfig(cfg),
+				noob.WithMeta(keyvalue.KeyValue{
+					"app_name":        "test",
+					"app_version":     "v0.1.0",
+					"app_description": "Description",
+				}),
+				noob.WithThrottlingCfg(noob.ThrottlingCfg{
+					MaxEventPerSec: 2,
+					MaxBurstSize:   1,
+				}),
+				noob.WithCORSCfg(noob.CORSCfg{
+					Enable:       true,
+					AllowOrigins: []string{"*"},
+				}),
+			)
 
 			g1 := app.Branch("/sample")
 
@@ -0,0 +1,98 @@
+package noob
+
+import (
+	"github.com/alfarih31/nb-go-http/utils"
+	"time"
+)
+
+// HeaderParser parse request headers with the same options as QueryParser, keys are case insensitive
+type HeaderParser HandlerCtx
+
+func (p HeaderParser) parser() valueParser {
+	return valueParser{name: "header", source: p.headerValues}
+}
+
+func (p HeaderParser) GetString(key string, opt ...QueryParserOption) (*string, error) {
+	return p.parser().getString(key, opt)
+}
+
+func (p HeaderParser) GetBool(key string, opt ...QueryParserOption) (*bool, error) {
+	return p.parser().getBool(key, opt)
+}
+
+func (p HeaderParser) GetInt(key string, opt ...QueryParserOption) (*int, error) {
+	return p.parser().getInt(key, opt)
+}
+
+// GetInt32 return value of key as int32, value out of int32 range is invalid
+func (p HeaderParser) GetInt32(key string, opt ...QueryParserOption) (*int32, error) {
+	return p.parser().getInt32(key, opt)
+}
+
+func (p HeaderParser) GetInt64(key string, opt ...QueryParserOption) (*int64, error) {
+	return p.parser().getInt64(key, opt)
+}
+
+func (p HeaderParser) GetFloat32(key string, opt ...QueryParserOption) (*float32, error) {
+	return p.parser().getFloat32(key, opt)
+}
+
+func (p HeaderParser) GetFloat64(key string, opt ...QueryParserOption) (*float64, error) {
+	return p.parser().getFloat64(key, opt)
+}
+
+// GetUint return value of key as uint, negative value is invalid
+func (p HeaderParser) GetUint(key string, opt ...QueryParserOption) (*uint, error) {
+	return p.parser().getUint(key, opt)
+}
+
+func (p HeaderParser) GetUint32(key string, opt ...QueryParserOption) (*uint32, error) {
+	return p.parser().getUint32(key, opt)
+}
+
+func (p HeaderParser) GetUint64(key string, opt ...QueryParserOption) (*uint64, error) {
+	return p.parser().getUint64(key, opt)
+}
+
+// GetDuration return value of key parsed by time.ParseDuration, e.g. 1m30s
+func (p HeaderParser) GetDuration(key string, opt ...QueryParserOption) (*time.Duration, error) {
+	return p.parser().getDuration(key, opt)
+}
+
+// GetTime return value of key parsed as RFC3339 timestamp. Default must be *utils.Datetime
+func (p HeaderParser) GetTime(key string, opt ...QueryParserOption) (*utils.Datetime, error) {
+	return p.parser().getTime(key, opt)
+}
+
+// GetStrings return repeated or comma separated values of key, e.g. Accept-Encoding: gzip, br
+func (p HeaderParser) GetStrings(key string, opt ...QueryParserOption) ([]string, error) {
+	return p.parser().getStrings(key, opt)
+}
+
+// GetInts return repeated or comma separated values of key as []int
+func (p HeaderParser) GetInts(key string, opt ...QueryParserOption) ([]int, error) {
+	return p.parser().getInts(key, opt)
+}
+
+func (p HeaderParser) GetInt64s(key string, opt ...QueryParserOption) ([]int64, error) {
+	return p.parser().getInt64s(key, opt)
+}
+
+func (p HeaderParser) GetFloat64s(key string, opt ...QueryParserOption) ([]float64, error) {
+	return p.parser().getFloat64s(key, opt)
+}
+
+// Bind bind request headers to fields of dst tagged by `header:"X-Name"` with the same rules as QueryParser.Bind
+func (p HeaderParser) Bind(dst interface{}) error {
+	return binder{sources: []binderSource{{tag: "header", source: p.headerValues}}}.bind(dst)
+}
+
+// headerValues return values of header key, empty values are missing
+func (p HeaderParser) headerValues(key string) ([]string, bool) {
+	values := p.Request.Header.Values(key)
+	if len(values) == 0 || (len(values) == 1 && values[0] == "") {
+		return nil, false
+	}
+
+	return values, true
+}
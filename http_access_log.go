@@ -0,0 +1,278 @@
+package noob
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AccessLogFormat is format of access log lines
+type AccessLogFormat string
+
+const (
+	// AccessLogCombined is Apache combined log format, AccessLogCfg.Fields is not used
+	AccessLogCombined AccessLogFormat = "combined"
+
+	// AccessLogJSON is JSON object per line
+	AccessLogJSON AccessLogFormat = "json"
+
+	// AccessLogLogfmt is key=value pairs per line
+	AccessLogLogfmt AccessLogFormat = "logfmt"
+)
+
+// AccessLogField is field of JSON & logfmt access log lines
+type AccessLogField string
+
+const (
+	AccessLogTime      AccessLogField = "time"
+	AccessLogClientIP  AccessLogField = "client_ip"
+	AccessLogMethod    AccessLogField = "method"
+	AccessLogPath      AccessLogField = "path"
+	AccessLogRoute     AccessLogField = "route"
+	AccessLogProto     AccessLogField = "proto"
+	AccessLogStatus    AccessLogField = "status"
+	AccessLogSize      AccessLogField = "size"
+	AccessLogLatency   AccessLogField = "latency"
+	AccessLogUserAgent AccessLogField = "user_agent"
+	AccessLogReferer   AccessLogField = "referer"
+	AccessLogRequestID AccessLogField = "request_id"
+)
+
+// DefaultAccessLogFields is fields logged when AccessLogCfg.Fields is empty
+var DefaultAccessLogFields = []AccessLogField{
+	AccessLogTime, AccessLogClientIP, AccessLogMethod, AccessLogPath, AccessLogRoute, AccessLogProto, AccessLogStatus,
+	AccessLogSize, AccessLogLatency, AccessLogUserAgent, AccessLogReferer, AccessLogRequestID,
+}
+
+// AccessLogCfg is configuration of access log middleware
+type AccessLogCfg struct {
+	// Format of the lines, default to AccessLogJSON
+	Format AccessLogFormat
+
+	// Fields of JSON & logfmt lines in order, default to DefaultAccessLogFields
+	Fields []AccessLogField
+
+	// SkipPaths is request paths not logged, e.g. "/" for health checks
+	SkipPaths []string
+
+	// Output is sink of the lines, default to os.Stdout. Every line is a single Write
+	Output io.Writer
+}
+
+var accessLogFields = map[AccessLogField]func(e *accessLogEntry) interface{}{
+	AccessLogTime:      func(e *accessLogEntry) interface{} { return e.time.Format(time.RFC3339Nano) },
+	AccessLogClientIP:  func(e *accessLogEntry) interface{} { return e.clientIP },
+	AccessLogMethod:    func(e *accessLogEntry) interface{} { return e.method },
+	AccessLogPath:      func(e *accessLogEntry) interface{} { return e.path },
+	AccessLogRoute:     func(e *accessLogEntry) interface{} { return e.route },
+	AccessLogProto:     func(e *accessLogEntry) interface{} { return e.proto },
+	AccessLogStatus:    func(e *accessLogEntry) interface{} { return e.status },
+	AccessLogSize:      func(e *accessLogEntry) interface{} { return e.size },
+	AccessLogLatency:   func(e *accessLogEntry) interface{} { return e.latency.String() },
+	AccessLogUserAgent: func(e *accessLogEntry) interface{} { return e.userAgent },
+	AccessLogReferer:   func(e *accessLogEntry) interface{} { return e.referer },
+	AccessLogRequestID: func(e *accessLogEntry) interface{} { return e.requestID },
+}
+
+func (c AccessLogCfg) validate() Errors {
+	var errs Errors
+	switch c.Format {
+	case "", AccessLogCombined, AccessLogJSON, AccessLogLogfmt:
+	default:
+		errs = append(errs, newConfigError("AccessLogCfg.Format", fmt.Sprintf("'%s' is unknown", c.Format)))
+	}
+
+	for _, f := range c.Fields {
+		if _, ok := accessLogFields[f]; !ok {
+			errs = append(errs, newConfigError("AccessLogCfg.Fields", fmt.Sprintf("'%s' is unknown", f)))
+		}
+	}
+
+	return errs
+}
+
+// HandleAccessLog return middleware writing access log line of every request to cfg.Output. It panic if cfg is invalid
+func HandleAccessLog(cfg AccessLogCfg) HandlerFunc {
+	h, errs := newAccessLogHandler(cfg)
+	if errs != nil {
+		panic(NewCoreError(fmt.Sprintf("HandleAccessLog: %v", errs)))
+	}
+
+	return h
+}
+
+func newAccessLogHandler(cfg AccessLogCfg) (HandlerFunc, Errors) {
+	if errs := cfg.validate(); errs != nil {
+		return nil, errs
+	}
+
+	if cfg.Format == "" {
+		cfg.Format = AccessLogJSON
+	}
+
+	if len(cfg.Fields) == 0 {
+		cfg.Fields = DefaultAccessLogFields
+	}
+
+	if cfg.Output == nil {
+		cfg.Output = os.Stdout
+	}
+
+	skip := map[string]bool{}
+	for _, p := range cfg.SkipPaths {
+		skip[p] = true
+	}
+
+	var mu sync.Mutex
+
+	return func(c *HandlerCtx) (res Response, err error) {
+		if skip[c.Request.URL.Path] {
+			return c.Next()
+		}
+
+		start := time.Now()
+		defer func() {
+			e := newAccessLogEntry(c, start, c.responseStatus(res, err))
+
+			var line []byte
+			switch cfg.Format {
+			case AccessLogCombined:
+				line = e.combined()
+			case AccessLogLogfmt:
+				line = e.logfmt(cfg.Fields)
+			default:
+				line = e.json(cfg.Fields)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if _, err := cfg.Output.Write(line); err != nil {
+				c.logger(log).Error(fmt.Sprintf("write access log error, %v", err))
+			}
+		}()
+
+		return c.Next()
+	}, nil
+}
+
+type accessLogEntry struct {
+	time      time.Time
+	clientIP  string
+	method    string
+	path      string
+	uri       string
+	route     string
+	proto     string
+	status    int
+	size      int
+	latency   time.Duration
+	userAgent string
+	referer   string
+	requestID string
+}
+
+func newAccessLogEntry(c *HandlerCtx, start time.Time, status int) *accessLogEntry {
+	size := c.Writer.Size()
+	if size < 0 {
+		size = 0
+	}
+
+	return &accessLogEntry{
+		time:      start,
+		clientIP:  c.ClientIP(),
+		method:    c.Request.Method,
+		path:      c.Request.URL.Path,
+		uri:       c.Request.RequestURI,
+		route:     c.FullPath(),
+		proto:     c.Request.Proto,
+		status:    status,
+		size:      size,
+		latency:   time.Since(start),
+		userAgent: c.Request.UserAgent(),
+		referer:   c.Request.Referer(),
+		requestID: c.RequestID(),
+	}
+}
+
+// combined return line in Apache combined log format: %h %l %u %t "%r" %>s %b "%{Referer}i" "%{User-agent}i"
+func (e *accessLogEntry) combined() []byte {
+	size := "-"
+	if e.size > 0 {
+		size = strconv.Itoa(e.size)
+	}
+
+	uri := e.uri
+	if uri == "" {
+		uri = e.path
+	}
+
+	return []byte(fmt.Sprintf("%s - - [%s] \"%s %s %s\" %d %s \"%s\" \"%s\"\n",
+		e.clientIP, e.time.Format("02/Jan/2006:15:04:05 -0700"), e.method, combinedEscape(uri), e.proto, e.status, size,
+		combinedEscape(e.referer), combinedEscape(e.userAgent)))
+}
+
+// combinedEscape escape quote, backslash & non printable characters like Apache
+func combinedEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case ch == '"' || ch == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(ch)
+		case ch < ' ' || ch > '~':
+			fmt.Fprintf(&b, "\\x%02x", ch)
+		default:
+			b.WriteByte(ch)
+		}
+	}
+
+	return b.String()
+}
+
+func (e *accessLogEntry) json(fields []AccessLogField) []byte {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		k, _ := json.Marshal(string(f))
+		v, _ := json.Marshal(accessLogFields[f](e))
+
+		b.Write(k)
+		b.WriteByte(':')
+		b.Write(v)
+	}
+	b.WriteString("}\n")
+
+	return b.Bytes()
+}
+
+func (e *accessLogEntry) logfmt(fields []AccessLogField) []byte {
+	var b bytes.Buffer
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+
+		b.WriteString(string(f))
+		b.WriteByte('=')
+
+		v := fmt.Sprint(accessLogFields[f](e))
+		if v == "" || strings.ContainsAny(v, " =\"\\") || strings.IndexFunc(v, func(r rune) bool { return r < ' ' || r == 0x7f }) >= 0 {
+			v = strconv.Quote(v)
+		}
+		b.WriteString(v)
+	}
+	b.WriteByte('\n')
+
+	return b.Bytes()
+}
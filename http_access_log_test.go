@@ -0,0 +1,151 @@
+package noob
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func newAccessLogTestApp(t *testing.T, cfg AccessLogCfg) (http.Handler, *bytes.Buffer) {
+	t.Helper()
+
+	out := new(bytes.Buffer)
+	cfg.Output = out
+
+	app := NewWithOptions(WithAccessLog(cfg))
+	app.GET("/users/:id", func(c *HandlerCtx) (Response, error) {
+		return NewResponseSuccess(ResponseBody{Data: c.Param("id")}), nil
+	})
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	return h, out
+}
+
+func serveAccessLog(h http.Handler, target string) {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("User-Agent", `curl "quoted"`)
+	req.Header.Set("Referer", "http://a.example/")
+	req.Header.Set(HeaderRequestID, "req-1")
+
+	h.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestAccessLogJSON(t *testing.T) {
+	h, out := newAccessLogTestApp(t, AccessLogCfg{})
+
+	serveAccessLog(h, "/users/42?q=1")
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &line); err != nil {
+		t.Fatalf("decode line error: %v, line: %s", err, out.String())
+	}
+
+	want := map[string]interface{}{
+		"client_ip":  "10.0.0.1",
+		"method":     "GET",
+		"path":       "/users/42",
+		"route":      "/users/:id",
+		"proto":      "HTTP/1.1",
+		"status":     float64(200),
+		"user_agent": `curl "quoted"`,
+		"referer":    "http://a.example/",
+		"request_id": "req-1",
+	}
+	for k, v := range want {
+		if line[k] != v {
+			t.Fatalf("expected %s = %v, got %v", k, v, line[k])
+		}
+	}
+
+	if len(line) != len(DefaultAccessLogFields) || line["size"].(float64) <= 0 {
+		t.Fatalf("expected all default fields, got %v", line)
+	}
+
+	// Fields are written in order
+	if !strings.HasPrefix(out.String(), `{"time":`) {
+		t.Fatalf("expected time first, got %s", out.String())
+	}
+}
+
+func TestAccessLogLogfmt(t *testing.T) {
+	h, out := newAccessLogTestApp(t, AccessLogCfg{
+		Format: AccessLogLogfmt,
+		Fields: []AccessLogField{AccessLogMethod, AccessLogRoute, AccessLogStatus, AccessLogUserAgent, AccessLogRequestID},
+	})
+
+	serveAccessLog(h, "/users/42")
+
+	want := `method=GET route=/users/:id status=200 user_agent="curl \"quoted\"" request_id=req-1` + "\n"
+	if out.String() != want {
+		t.Fatalf("expected %q, got %q", want, out.String())
+	}
+}
+
+func TestAccessLogCombined(t *testing.T) {
+	h, out := newAccessLogTestApp(t, AccessLogCfg{Format: AccessLogCombined})
+
+	serveAccessLog(h, "/users/42?q=1")
+
+	pattern := regexp.MustCompile(`^10\.0\.0\.1 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /users/42\?q=1 HTTP/1\.1" 200 \d+ "http://a\.example/" "curl \\"quoted\\""` + "\n$")
+	if !pattern.MatchString(out.String()) {
+		t.Fatalf("unexpected combined line %q", out.String())
+	}
+}
+
+func TestAccessLogSkipPaths(t *testing.T) {
+	h, out := newAccessLogTestApp(t, AccessLogCfg{SkipPaths: []string{"/"}})
+
+	serveAccessLog(h, "/")
+	if out.Len() != 0 {
+		t.Fatalf("expected skipped path not logged, got %s", out.String())
+	}
+
+	serveAccessLog(h, "/missing")
+	if !strings.Contains(out.String(), `"status":404`) {
+		t.Fatalf("expected not found logged, got %s", out.String())
+	}
+}
+
+func TestAccessLogInvalid(t *testing.T) {
+	app := NewWithOptions(WithAccessLog(AccessLogCfg{Format: "xml", Fields: []AccessLogField{"bytes"}}))
+
+	_, err := app.Handler()
+
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("expected 2 config errors, got %v", err)
+	}
+}
+
+func TestAccessLogMiddlewareErrorStatus(t *testing.T) {
+	out := new(bytes.Buffer)
+
+	app := NewWithOptions(
+		WithAccessLog(AccessLogCfg{Fields: []AccessLogField{AccessLogStatus}, Output: out}),
+		WithThrottlingCfg(ThrottlingCfg{Enable: true, MaxEventPerSec: 1, MaxBurstSize: 1}),
+	)
+	app.GET("/users/:id", routesHandler)
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	// Error of the throttling middleware is sent after the access log middleware return
+	serveAccessLog(h, "/users/1")
+	serveAccessLog(h, "/users/1")
+
+	if want := "{\"status\":200}\n{\"status\":429}\n"; out.String() != want {
+		t.Fatalf("expected %q, got %q", want, out.String())
+	}
+}
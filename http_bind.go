@@ -0,0 +1,239 @@
+package noob
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// DefaultMaxBodySize is max size in bytes of request body read by HandlerCtx.Bind when Cfg.MaxBodySize is 0
+const DefaultMaxBodySize = 1 << 20
+
+var errBodyTooLarge = errors.New("request body too large")
+
+// FieldError is violation of a field of value bound by HandlerCtx.Bind or Bind of QueryParser, ParamParser, HeaderParser &
+// RequestParser
+type FieldError struct {
+	// Field is path of the field as named in the request, e.g. address.city or items[0].name
+	Field string `json:"field"`
+
+	// Rule is the violated rule, e.g. required, max or type
+	Rule string `json:"rule"`
+
+	// Param is parameter of the rule, e.g. 10 of max=10
+	Param string `json:"param,omitempty"`
+
+	Message string `json:"message"`
+}
+
+// FieldErrors is list of FieldError, it is _error of DefaultUnprocessableEntityErrorResponse returned by HandlerCtx.Bind &
+// Bind of the parsers
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fmt.Sprintf("%s %s", fe.Field, fe.Message)
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// bodyBindings is binding & tag naming the fields of each Content-Type
+var bodyBindings = map[string]struct {
+	binding binding.Binding
+	tag     string
+}{
+	binding.MIMEJSON:              {binding.JSON, "json"},
+	binding.MIMEXML:               {binding.XML, "xml"},
+	binding.MIMEXML2:              {binding.XML, "xml"},
+	binding.MIMEPOSTForm:          {binding.Form, "form"},
+	binding.MIMEMultipartPOSTForm: {binding.FormMultipart, "form"},
+}
+
+// Bind decode request body to dst by Content-Type, JSON when it is missing, then validate dst by `binding` tags.
+// Supported Content-Type are JSON, XML, URL encoded & multipart form. Body larger than Cfg.MaxBodySize is rejected.
+//
+// The returned error is ResponseError, it can be returned by the handler as is:
+// DefaultUnprocessableEntityErrorResponse with FieldErrors as _error when dst is invalid, DefaultBadRequestErrorResponse when
+// the body is malformed, DefaultRequestEntityTooLargeErrorResponse or DefaultUnsupportedMediaTypeErrorResponse
+func (c *HandlerCtx) Bind(dst interface{}) error {
+	contentType := c.ContentType()
+	if contentType == "" {
+		contentType = binding.MIMEJSON
+	}
+
+	b, ok := bodyBindings[contentType]
+	if !ok {
+		return DefaultUnsupportedMediaTypeErrorResponse.SetMessage(fmt.Sprintf("unsupported media type '%s'", contentType))
+	}
+
+	limit := c.cfg().MaxBodySize
+	if limit == 0 {
+		limit = DefaultMaxBodySize
+	}
+
+	body := &maxBodyReader{ReadCloser: c.Request.Body, remaining: int64(limit)}
+	if c.Request.Body != nil {
+		c.Request.Body = body
+	}
+
+	err := c.ShouldBindWith(dst, b.binding)
+	if err == nil {
+		return nil
+	}
+
+	if body.exceeded {
+		return DefaultRequestEntityTooLargeErrorResponse.SetMessage(fmt.Sprintf("request body is larger than %d bytes", limit))
+	}
+
+	return bindError(err, reflect.TypeOf(dst), b.tag)
+}
+
+// bindError convert err of binding dst of type t to ResponseError, fields are named by tag
+func bindError(err error, t reflect.Type, tag string) ResponseError {
+	var (
+		validationErrs validator.ValidationErrors
+		typeErr        *json.UnmarshalTypeError
+	)
+
+	switch {
+	case errors.As(err, &validationErrs):
+		fes := make(FieldErrors, len(validationErrs))
+		for i, ve := range validationErrs {
+			fes[i] = FieldError{
+				Field:   fieldPath(t, ve.StructNamespace(), tag),
+				Rule:    ve.Tag(),
+				Param:   ve.Param(),
+				Message: ruleMessage(ve.Tag(), ve.Param()),
+			}
+		}
+
+		return DefaultUnprocessableEntityErrorResponse.SetError(fes)
+	case errors.As(err, &typeErr):
+		return DefaultUnprocessableEntityErrorResponse.SetError(FieldErrors{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Param:   typeErr.Type.String(),
+			Message: fmt.Sprintf("must be %s, got %s", typeErr.Type, typeErr.Value),
+		}})
+	case err == io.EOF:
+		return DefaultBadRequestErrorResponse.SetMessage("request body is empty")
+	}
+
+	return DefaultBadRequestErrorResponse.SetMessage(fmt.Sprintf("malformed request body, %v", err))
+}
+
+// ruleMessage return message of violated validation rule
+func ruleMessage(rule string, param string) string {
+	switch rule {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return fmt.Sprintf("must be at least %s", param)
+	case "max", "lte":
+		return fmt.Sprintf("must be at most %s", param)
+	case "len":
+		return fmt.Sprintf("must have length %s", param)
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", param)
+	}
+
+	if param != "" {
+		return fmt.Sprintf("must satisfy %s=%s", rule, param)
+	}
+
+	return fmt.Sprintf("must satisfy %s", rule)
+}
+
+// fieldPath convert struct namespace of validator, e.g. Root.Address.City or Root.Items[0].Name, to path named by tag of the
+// fields. Fields of embedded struct are promoted like encoding/json
+func fieldPath(t reflect.Type, namespace string, tag string) string {
+	segments := strings.Split(namespace, ".")[1:]
+
+	var path []string
+	for _, seg := range segments {
+		name, index := seg, ""
+		if i := strings.IndexByte(seg, '['); i >= 0 {
+			name, index = seg[:i], seg[i:]
+		}
+
+		t = elemType(t)
+		if t.Kind() != reflect.Struct {
+			path = append(path, seg)
+			continue
+		}
+
+		f, ok := t.FieldByName(name)
+		if !ok {
+			path = append(path, seg)
+			continue
+		}
+
+		t = f.Type
+		if index != "" {
+			t = elemType(t)
+		}
+
+		n := strings.Split(f.Tag.Get(tag), ",")[0]
+		if n == "" && f.Anonymous && index == "" {
+			continue
+		}
+
+		if n == "" || n == "-" {
+			n = f.Name
+		}
+
+		path = append(path, n+index)
+	}
+
+	return strings.Join(path, ".")
+}
+
+// elemType dereference pointer & return element type of slice, array & map
+func elemType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+// maxBodyReader return errBodyTooLarge when more than remaining bytes are read
+type maxBodyReader struct {
+	io.ReadCloser
+	remaining int64
+	exceeded  bool
+}
+
+func (r *maxBodyReader) Read(p []byte) (int, error) {
+	if r.exceeded {
+		return 0, errBodyTooLarge
+	}
+
+	// Read a byte more than remaining to detect the body is too large
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+
+	n, err := r.ReadCloser.Read(p)
+	if int64(n) <= r.remaining {
+		r.remaining -= int64(n)
+
+		return n, err
+	}
+
+	n = int(r.remaining)
+	r.remaining = 0
+	r.exceeded = true
+
+	return n, errBodyTooLarge
+}
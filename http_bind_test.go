@@ -0,0 +1,238 @@
+package noob
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type bindTestAudit struct {
+	CreatedBy string `json:"created_by" form:"created_by" binding:"required"`
+}
+
+type bindTestItem struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type bindTestUser struct {
+	bindTestAudit
+
+	Name    string `json:"name" form:"name" binding:"required,max=5"`
+	Age     int    `json:"age" form:"age" binding:"gte=0"`
+	Address *struct {
+		City string `json:"city" binding:"required"`
+	} `json:"address" binding:"required"`
+	Items []bindTestItem `json:"items" binding:"dive"`
+}
+
+type bindTestForm struct {
+	Name string                `form:"name" binding:"required"`
+	Age  int                   `form:"age"`
+	File *multipart.FileHeader `form:"file"`
+}
+
+func newBindTestApp(t *testing.T, maxBodySize int) http.Handler {
+	t.Helper()
+
+	app := NewWithOptions(WithCfg(Cfg{MaxBodySize: maxBodySize}))
+	app.POST("/users", func(c *HandlerCtx) (Response, error) {
+		var u bindTestUser
+		if err := c.Bind(&u); err != nil {
+			return nil, err
+		}
+
+		return NewResponseSuccess(ResponseBody{Data: u}), nil
+	})
+	app.POST("/forms", func(c *HandlerCtx) (Response, error) {
+		var f bindTestForm
+		if err := c.Bind(&f); err != nil {
+			return nil, err
+		}
+
+		file := ""
+		if f.File != nil {
+			file = f.File.Filename
+		}
+
+		return NewResponseSuccess(ResponseBody{Data: map[string]interface{}{"name": f.Name, "age": f.Age, "file": file}}), nil
+	})
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	return h
+}
+
+type bindTestBody struct {
+	Message string                 `json:"message"`
+	Data    map[string]interface{} `json:"data"`
+	Errors  []FieldError           `json:"_error"`
+}
+
+func serveBind(t *testing.T, h http.Handler, path string, contentType string, body string) (int, bindTestBody) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var b bindTestBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &b); err != nil {
+		// _error is string for non validation errors
+		var s struct {
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &s); err != nil {
+			t.Fatalf("decode body error: %v, body: %s", err, rec.Body.String())
+		}
+		b.Message = s.Message
+	}
+
+	return rec.Code, b
+}
+
+func TestBindJSON(t *testing.T) {
+	h := newBindTestApp(t, 0)
+
+	code, body := serveBind(t, h, "/users", "application/json; charset=utf-8",
+		`{"created_by":"admin","name":"bob","age":3,"address":{"city":"Bandung"},"items":[{"name":"a"}]}`)
+	if code != http.StatusOK || body.Data["name"] != "bob" || body.Data["created_by"] != "admin" {
+		t.Fatalf("expected bound user, got %d %v", code, body)
+	}
+
+	// Missing Content-Type is JSON
+	code, _ = serveBind(t, h, "/users", "", `{"created_by":"admin","name":"bob","address":{"city":"Bandung"}}`)
+	if code != http.StatusOK {
+		t.Fatalf("expected 200 without content type, got %d", code)
+	}
+}
+
+func TestBindValidation(t *testing.T) {
+	h := newBindTestApp(t, 0)
+
+	code, body := serveBind(t, h, "/users", "application/json",
+		`{"name":"robert","age":-1,"address":{},"items":[{"name":"a"},{}]}`)
+	if code != http.StatusUnprocessableEntity || body.Message != "unprocessable entity" {
+		t.Fatalf("expected 422, got %d %v", code, body)
+	}
+
+	want := []FieldError{
+		{Field: "created_by", Rule: "required", Message: "is required"},
+		{Field: "name", Rule: "max", Param: "5", Message: "must be at most 5"},
+		{Field: "age", Rule: "gte", Param: "0", Message: "must be at least 0"},
+		{Field: "address.city", Rule: "required", Message: "is required"},
+		{Field: "items[1].name", Rule: "required", Message: "is required"},
+	}
+	if len(body.Errors) != len(want) {
+		t.Fatalf("expected %d field errors, got %v", len(want), body.Errors)
+	}
+
+	for i, fe := range want {
+		if body.Errors[i] != fe {
+			t.Fatalf("expected %v, got %v", fe, body.Errors[i])
+		}
+	}
+}
+
+func TestBindTypeMismatch(t *testing.T) {
+	h := newBindTestApp(t, 0)
+
+	code, body := serveBind(t, h, "/users", "application/json", `{"name":"bob","age":"old"}`)
+	if code != http.StatusUnprocessableEntity || len(body.Errors) != 1 || body.Errors[0].Field != "age" ||
+		body.Errors[0].Rule != "type" || body.Errors[0].Param != "int" {
+		t.Fatalf("expected type violation of age, got %d %v", code, body)
+	}
+}
+
+func TestBindMalformed(t *testing.T) {
+	h := newBindTestApp(t, 0)
+
+	for _, b := range []string{`{"name":`, ``} {
+		if code, _ := serveBind(t, h, "/users", "application/json", b); code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %q, got %d", b, code)
+		}
+	}
+}
+
+func TestBindMaxBodySize(t *testing.T) {
+	h := newBindTestApp(t, 16)
+
+	code, _ := serveBind(t, h, "/users", "application/json", `{"name":"bob","created_by":"admin"}`)
+	if code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d", code)
+	}
+
+	code, _ = serveBind(t, h, "/forms", "application/x-www-form-urlencoded", url.Values{"name": {strings.Repeat("a", 32)}}.Encode())
+	if code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413 for form, got %d", code)
+	}
+}
+
+func TestBindUnsupportedMediaType(t *testing.T) {
+	h := newBindTestApp(t, 0)
+
+	code, body := serveBind(t, h, "/users", "text/plain", `name=bob`)
+	if code != http.StatusUnsupportedMediaType || !strings.Contains(body.Message, "text/plain") {
+		t.Fatalf("expected 415, got %d %v", code, body)
+	}
+}
+
+func TestBindForm(t *testing.T) {
+	h := newBindTestApp(t, 0)
+
+	code, body := serveBind(t, h, "/forms", "application/x-www-form-urlencoded", url.Values{"name": {"bob"}, "age": {"3"}}.Encode())
+	if code != http.StatusOK || body.Data["name"] != "bob" || body.Data["age"] != float64(3) {
+		t.Fatalf("expected bound form, got %d %v", code, body)
+	}
+
+	code, body = serveBind(t, h, "/forms", "application/x-www-form-urlencoded", "age=3")
+	if code != http.StatusUnprocessableEntity || len(body.Errors) != 1 || body.Errors[0].Field != "name" {
+		t.Fatalf("expected violation of name, got %d %v", code, body)
+	}
+}
+
+func TestBindMultipart(t *testing.T) {
+	h := newBindTestApp(t, 0)
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	_ = w.WriteField("name", "bob")
+	fw, _ := w.CreateFormFile("file", "avatar.png")
+	_, _ = fw.Write([]byte("png"))
+	_ = w.Close()
+
+	code, body := serveBind(t, h, "/forms", w.FormDataContentType(), buf.String())
+	if code != http.StatusOK || body.Data["name"] != "bob" || body.Data["file"] != "avatar.png" {
+		t.Fatalf("expected bound multipart form, got %d %v", code, body)
+	}
+}
+
+func TestBindValidationDebug(t *testing.T) {
+	app := NewWithOptions(WithDebug(true))
+	app.POST("/users", func(c *HandlerCtx) (Response, error) {
+		var u bindTestUser
+		return nil, c.Bind(&u)
+	})
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	// Field errors are kept in debug mode
+	code, body := serveBind(t, h, "/users", "application/json", `{"name":"bob","created_by":"admin"}`)
+	if code != http.StatusUnprocessableEntity || len(body.Errors) != 1 || body.Errors[0].Field != "address" {
+		t.Fatalf("expected violation of address, got %d %v", code, body)
+	}
+}
@@ -1,46 +1,54 @@
 package noob
 
 import (
-	"context"
 	"fmt"
 	"github.com/alfarih31/nb-go-keyvalue"
 	logger "github.com/alfarih31/nb-go-logger"
+	"github.com/gin-gonic/gin"
 	"golang.org/x/time/rate"
+	"net/http"
+	"strings"
 	"time"
 )
 
 func APIStatus() keyvalue.KeyValue {
+	return apiStatus(DefaultMeta)
+}
+
+func apiStatus(meta keyvalue.KeyValue) keyvalue.KeyValue {
 	u := time.Since(StartTime).String()
 
 	res := keyvalue.KeyValue{
 		"uptime": u,
 	}
 
-	res.Assign(DefaultMeta, true)
+	res.Assign(meta, true)
 
 	return res
 }
 
 func HandleAPIStatus(c *HandlerCtx) (Response, error) {
 	return NewResponseSuccess(ResponseBody{
-		Data: APIStatus(),
+		Data: apiStatus(c.meta()),
 	}), nil
 }
 
 func handleRequestLogger(logger logger.Logger) HandlerFunc {
-	return func(c *HandlerCtx) (Response, error) {
+	return func(c *HandlerCtx) (res Response, err error) {
 		start := time.Now()
 
 		log := func() {
 			latency := time.Since(start)
-			logger.Info(
+			status := c.responseStatus(res, err)
+			c.logger(logger).Info(
 				fmt.Sprintf(
-					"%s - %s %s %d - %s",
-					c.ClientIP(), c.Request.Method, c.Request.URL.Path, c.Writer.Status(), latency), map[string]interface{}{
+					"%s - %s %s %s %d - %s",
+					c.ClientIP(), c.Request.Method, c.Request.URL.Path, c.Request.Proto, status, latency), map[string]interface{}{
 					"clientIp": c.ClientIP(),
 					"method":   c.Request.Method,
 					"path":     c.Request.URL.Path,
-					"status":   c.Writer.Status(),
+					"proto":    c.Request.Proto,
+					"status":   status,
 					"latency":  latency.String(),
 				})
 		}
@@ -52,65 +60,83 @@ func handleRequestLogger(logger logger.Logger) HandlerFunc {
 }
 
 func HandleNotFound(context *HandlerCtx) (Response, error) {
-	// Don't handle if http version > 1
-	if context.Request.ProtoMajor > 1 {
+	// Don't handle HTTP/2 connection preface, e.g. prior knowledge h2c request to HTTP/1 server
+	if context.Request.ProtoMajor > 1 && context.Request.Method == "PRI" {
 		return context.Next()
 	}
 
 	return nil, DefaultNotFoundErrorResponse
 }
 
-func HandleTimeout(c *HandlerCtx) (Response, error) {
-	if DefaultCfg.RequestTimeout > 0 {
-		timeoutCtx, cancel := context.WithTimeout(c.Request.Context(), DefaultCfg.RequestTimeout)
-		defer cancel()
-
-		resChan := make(chan Response)
-		errChan := make(chan error)
+// newMethodNotAllowedHandler return handler responding method not allowed with Allow header of methods registered to engine for the path.
+// OPTIONS is answered with the Allow header, preflight requests are answered by CORS middleware when CORS is enabled
+func newMethodNotAllowedHandler(engine *gin.Engine) HandlerFunc {
+	m := newRouteMatcher(engine)
 
-		go func() {
-			res, err := c.Next()
+	return func(c *HandlerCtx) (Response, error) {
+		methods := m.methods(c.Request.URL.Path)
+		if len(methods) == 0 {
+			return nil, DefaultNotFoundErrorResponse
+		}
 
-			if err != nil {
-				errChan <- err
-			}
+		// OPTIONS is always allowed, as it is answered automatically
+		allow := strings.Join(methods, ", ")
+		if !strings.Contains(allow, http.MethodOptions) {
+			allow += ", " + http.MethodOptions
+		}
+		c.Writer.Header().Set("Allow", allow)
 
-			resChan <- res
-		}()
-
-		select {
-		case res := <-resChan:
-			return res, nil
-		case err := <-errChan:
-			return nil, err
-		case <-timeoutCtx.Done():
-			r := DefaultRequestTimeoutErrorResponse
-			if err := c.response(r.GetCode(), r.GetBody(), r.GetHeader()); err != nil {
-				log.Error(err)
-			}
+		if c.Request.Method == http.MethodOptions {
+			return DefaultSuccessNoContentResponse, nil
 		}
 
-		return nil, nil
+		return nil, DefaultMethodNotAllowedErrorResponse
 	}
-
-	return c.Next()
 }
 
+// HandleThrottling return throttling handler configured by DefaultThrottlingCfg
 func HandleThrottling() HandlerFunc {
-	cfg := DefaultThrottlingCfg
+	return newThrottlingHandler(DefaultThrottlingCfg)
+}
+
+func newThrottlingHandler(cfg ThrottlingCfg) HandlerFunc {
+	var limiter *rate.Limiter
+	if cfg.Enable && cfg.Store == nil {
+		limiter = rate.NewLimiter(rate.Limit(cfg.MaxEventPerSec), cfg.MaxBurstSize)
+	}
 
-	if !cfg.Enable {
-		return func(context *HandlerCtx) (Response, error) {
+	return func(context *HandlerCtx) (Response, error) {
+		c, l, key := cfg, limiter, "throttling"
+
+		// Route with throttling override use limiter of the override, nil if throttling is disabled
+		if o := context.routeOverrides(); o != nil && o.throttling != nil {
+			c, l, key = *o.throttling, o.limiter, "throttling:"+o.throttlingScope
+		}
+
+		if !c.Enable {
 			return context.Next()
 		}
-	}
 
-	limiter := rate.NewLimiter(rate.Limit(cfg.MaxEventPerSec), cfg.MaxBurstSize)
+		if c.Store != nil {
+			r, err := c.Store.TakeToken(context.Request.Context(), key, float64(c.MaxEventPerSec), c.MaxBurstSize, time.Now())
+			if err != nil {
+				context.logger(log).Error(fmt.Sprintf("throttling store error, %v", err))
+				return context.Next()
+			}
 
-	return func(context *HandlerCtx) (Response, error) {
-		if limiter.Allow() {
+			if r.Allowed {
+				return context.Next()
+			}
+
+			context.Set(extKeyRejected, rejectedThrottled)
+			return nil, DefaultTooManyRequestsErrorResponse
+		}
+
+		if l.Allow() {
 			return context.Next()
 		}
+
+		context.Set(extKeyRejected, rejectedThrottled)
 		return nil, DefaultTooManyRequestsErrorResponse
 	}
 }
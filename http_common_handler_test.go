@@ -0,0 +1,159 @@
+package noob
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHandleNotFoundTLSHTTP2(t *testing.T) {
+	ca := newTestCert(t, "ca", nil)
+	server := newTestCert(t, "server", ca)
+
+	app := newTestApp(t, func(cfg *Cfg) {
+		cfg.TLS = TLSCfg{Config: &tls.Config{Certificates: []tls.Certificate{server.tlsCert}}}
+	})
+	app.start(t)
+	defer app.stop(t)
+
+	cl := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: ca.pool()},
+		ForceAttemptHTTP2: true,
+	}}
+
+	res, err := cl.Get("https://" + app.addr + "/missing")
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	defer res.Body.Close()
+
+	var body ResponseBody
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	if res.StatusCode != http.StatusNotFound || res.ProtoMajor != 2 || body.Message != "not found" {
+		t.Fatalf("expected 404 envelope over HTTP/2, got %d %s %+v", res.StatusCode, res.Proto, body)
+	}
+}
+
+func TestHandleNotFoundSkipHTTP2Preface(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.start(t)
+	defer app.stop(t)
+
+	conn, err := net.Dial("tcp", app.addr)
+	if err != nil {
+		t.Fatalf("dial error: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	// HTTP/2 connection preface reaching HTTP/1 server is not a request for a route
+	if _, err := io.WriteString(conn, "PRI * HTTP/2.0\r\n\r\nSM\r\n\r\n"); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	res, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatalf("read response error: %v", err)
+	}
+	defer res.Body.Close()
+
+	b, _ := io.ReadAll(res.Body)
+	if res.StatusCode != http.StatusNotFound || string(b) != "404 page not found" {
+		t.Fatalf("expected plain 404 for connection preface, got %d %q", res.StatusCode, b)
+	}
+}
+
+func serveMethod(t *testing.T, h http.Handler, method string, path string, header ...string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, path, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func newMethodTestApp(t *testing.T, opts ...Option) http.Handler {
+	t.Helper()
+
+	app := NewWithOptions(opts...)
+	users := app.Branch("/users")
+	users.GET("", routesHandler)
+	users.POST("", routesHandler)
+	users.PUT("/:id", routesHandler)
+	users.DELETE("/:id", routesHandler)
+	app.GET("/files/*path", routesHandler)
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	return h
+}
+
+func TestHandleMethodNotAllowed(t *testing.T) {
+	h := newMethodTestApp(t)
+
+	cases := []struct {
+		method string
+		path   string
+		allow  string
+	}{
+		{http.MethodDelete, "/users", "GET, POST, OPTIONS"},
+		{http.MethodGet, "/users/1", "DELETE, PUT, OPTIONS"},
+		{http.MethodPost, "/files/a/b.txt", "GET, OPTIONS"},
+	}
+
+	for _, c := range cases {
+		rec := serveMethod(t, h, c.method, c.path)
+
+		var body ResponseBody
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode error: %v", err)
+		}
+
+		if rec.Code != http.StatusMethodNotAllowed || body.Message != "method not allowed" || rec.Header().Get("Allow") != c.allow {
+			t.Fatalf("%s %s: expected 405 with Allow %q, got %d %q %s", c.method, c.path, c.allow, rec.Code, rec.Header().Get("Allow"), rec.Body)
+		}
+	}
+
+	if rec := serveMethod(t, h, http.MethodPost, "/missing"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown path, got %d", rec.Code)
+	}
+}
+
+func TestHandleOptionsAutomatically(t *testing.T) {
+	h := newMethodTestApp(t, WithCORSCfg(CORSCfg{Enable: false}))
+
+	rec := serveMethod(t, h, http.MethodOptions, "/users/1")
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Allow") != "DELETE, PUT, OPTIONS" {
+		t.Fatalf("expected 204 with Allow, got %d %q", rec.Code, rec.Header().Get("Allow"))
+	}
+
+	if rec := serveMethod(t, h, http.MethodOptions, "/missing"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown path, got %d", rec.Code)
+	}
+}
+
+func TestHandleOptionsCORSPreflight(t *testing.T) {
+	h := newMethodTestApp(t, WithCORSCfg(CORSCfg{Enable: true, AllowMethods: "GET,POST"}))
+
+	rec := serveMethod(t, h, http.MethodOptions, "/users", "Origin", "http://example.com")
+	if rec.Code != http.StatusNoContent || rec.Header().Get(CORSAllowMethods) != "GET,POST" {
+		t.Fatalf("expected preflight answered by CORS, got %d %v", rec.Code, rec.Header())
+	}
+}
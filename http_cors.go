@@ -17,8 +17,8 @@ const CORSMaxAge = "Access-Control-Max-Age"
 type cors struct {
 }
 
-func (c cors) validateOrigins(origin string) bool {
-	for _, o := range DefaultCORSCfg.AllowOrigins {
+func (c cors) validateOrigins(cfg CORSCfg, origin string) bool {
+	for _, o := range cfg.AllowOrigins {
 		if o == "*" {
 			return true
 		}
@@ -32,7 +32,9 @@ func (c cors) validateOrigins(origin string) bool {
 }
 
 func (c cors) HandleCORS(ctx *HandlerCtx) (Response, error) {
-	if !DefaultCORSCfg.Enable {
+	cfg := ctx.corsCfg()
+
+	if !cfg.Enable {
 		return ctx.Next()
 	}
 
@@ -41,13 +43,11 @@ func (c cors) HandleCORS(ctx *HandlerCtx) (Response, error) {
 		return ctx.Next()
 	}
 
-	cfg := DefaultCORSCfg
-
 	if cfg.AllowOrigins == nil {
 		ctx.Writer.Header().Set(CORSAllowOrigin, origin)
 	} else {
 
-		if !c.validateOrigins(origin) {
+		if !c.validateOrigins(cfg, origin) {
 			return DefaultForbiddenErrorResponse, nil
 		}
 
@@ -55,26 +55,24 @@ func (c cors) HandleCORS(ctx *HandlerCtx) (Response, error) {
 	}
 
 	if ctx.Request.Method == http.MethodOptions {
-		c.handlePreflightRequest(ctx)
+		c.handlePreflightRequest(ctx, cfg)
 		return DefaultSuccessNoContentResponse, nil
 	}
 
-	c.handleNormalRequest(ctx)
+	c.handleNormalRequest(ctx, cfg)
 
 	return ctx.Next()
 }
 
-func (c cors) handlePreflightRequest(ctx *HandlerCtx) {
-	c.applyPreflightHeaders(ctx.Writer)
+func (c cors) handlePreflightRequest(ctx *HandlerCtx, cfg CORSCfg) {
+	c.applyPreflightHeaders(ctx.Writer, cfg)
 }
 
-func (c cors) handleNormalRequest(ctx *HandlerCtx) {
-	c.applyNormalHeaders(ctx.Writer)
+func (c cors) handleNormalRequest(ctx *HandlerCtx, cfg CORSCfg) {
+	c.applyNormalHeaders(ctx.Writer, cfg)
 }
 
-func (c cors) applyNormalHeaders(w http.ResponseWriter) {
-	cfg := DefaultCORSCfg
-
+func (c cors) applyNormalHeaders(w http.ResponseWriter, cfg CORSCfg) {
 	if cfg.AllowCredentials {
 		w.Header().Set(CORSAllowCredentials, parser.Bool(cfg.AllowCredentials).ToString())
 	}
@@ -86,9 +84,7 @@ func (c cors) applyNormalHeaders(w http.ResponseWriter) {
 	w.Header().Set("Vary", "Origin")
 }
 
-func (c cors) applyPreflightHeaders(w http.ResponseWriter) {
-	cfg := DefaultCORSCfg
-
+func (c cors) applyPreflightHeaders(w http.ResponseWriter, cfg CORSCfg) {
 	if cfg.AllowMethods != "" {
 		w.Header().Set(CORSAllowMethods, cfg.AllowMethods)
 	}
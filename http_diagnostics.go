@@ -0,0 +1,147 @@
+package noob
+
+import (
+	"bytes"
+	"github.com/DataDog/gostackparse"
+	keyvalue "github.com/alfarih31/nb-go-keyvalue"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+)
+
+// DefaultDiagnosticsPath is path of the branch mounted by MountDiagnostics
+const DefaultDiagnosticsPath = "/debug"
+
+// DiagnosticsCfg is configuration of diagnostics branch
+type DiagnosticsCfg struct {
+	// Path of the branch, default to DefaultDiagnosticsPath
+	Path string
+
+	// Guard authorize requests to the branch, e.g. by checking a token. Default to AllowLoopback, so the branch is not exposed
+	// publicly by accident
+	Guard HandlerFunc
+}
+
+// AllowLoopback is guard allowing only requests from loopback address. Forwarding headers are ignored, so requests behind a
+// reverse proxy are denied
+func AllowLoopback(c *HandlerCtx) (Response, error) {
+	host, _, err := net.SplitHostPort(c.Request.RemoteAddr)
+	if err != nil {
+		host = c.Request.RemoteAddr
+	}
+
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return c.Next()
+	}
+
+	return nil, DefaultForbiddenErrorResponse
+}
+
+// MountDiagnostics register branch of runtime diagnostics guarded by cfg.Guard & return it:
+//
+//	GET  {path}/pprof/        index of net/http/pprof
+//	GET  {path}/pprof/:name   profile by name, e.g. heap, goroutine, allocs, block & mutex
+//	GET  {path}/pprof/cmdline, profile, symbol & trace, POST {path}/pprof/symbol
+//	GET  {path}/goroutines    goroutine dump parsed by gostackparse
+//	GET  {path}/memstats      runtime.MemStats & GC stats
+//
+// Request timeout is disabled on the branch, so CPU profile & trace can run longer than Cfg.RequestTimeout
+func (e *Router) MountDiagnostics(cfgs ...DiagnosticsCfg) *Router {
+	var cfg DiagnosticsCfg
+	if len(cfgs) > 0 {
+		cfg = cfgs[0]
+	}
+
+	if cfg.Path == "" {
+		cfg.Path = DefaultDiagnosticsPath
+	}
+
+	if cfg.Guard == nil {
+		cfg.Guard = AllowLoopback
+	}
+
+	hidden := RouteDoc{Hidden: true}
+
+	b := e.Branch(cfg.Path, WithTimeout(0))
+	b.USE(cfg.Guard)
+
+	b.GET("/pprof/", handlePprofIndex).Doc(hidden)
+	b.GET("/pprof/cmdline", serveHTTPHandler(pprof.Cmdline)).Doc(hidden)
+	b.GET("/pprof/profile", serveHTTPHandler(pprof.Profile)).Doc(hidden)
+	b.GET("/pprof/symbol", serveHTTPHandler(pprof.Symbol)).Doc(hidden)
+	b.POST("/pprof/symbol", serveHTTPHandler(pprof.Symbol)).Doc(hidden)
+	b.GET("/pprof/trace", serveHTTPHandler(pprof.Trace)).Doc(hidden)
+	b.GET("/pprof/:name", handleProfile).Doc(hidden)
+	b.GET("/goroutines", handleGoroutines).Doc(hidden)
+	b.GET("/memstats", handleMemStats).Doc(hidden)
+
+	return b
+}
+
+// serveHTTPHandler return HandlerFunc serving the request by h
+func serveHTTPHandler(h http.HandlerFunc) HandlerFunc {
+	return func(c *HandlerCtx) (Response, error) {
+		h(c.Writer, c.Request)
+
+		return nil, nil
+	}
+}
+
+// pprofIndexPath is the only path pprof.Index serve the index on, other paths under /debug/pprof/ are looked up as profiles
+const pprofIndexPath = "/debug/pprof/"
+
+// handlePprofIndex serve index of net/http/pprof on any path of the branch, links of the index are relative to the path
+func handlePprofIndex(c *HandlerCtx) (Response, error) {
+	r := c.Request.Clone(c.Request.Context())
+	r.URL.Path = pprofIndexPath
+	r.URL.RawPath = ""
+
+	pprof.Index(c.Writer, r)
+
+	return nil, nil
+}
+
+// handleProfile serve profile by name under path of the branch, pprof.Index can't as it only look up under /debug/pprof/
+func handleProfile(c *HandlerCtx) (Response, error) {
+	pprof.Handler(c.Param("name")).ServeHTTP(c.Writer, c.Request)
+
+	return nil, nil
+}
+
+// handleGoroutines respond stacks of all goroutines parsed by gostackparse
+func handleGoroutines(c *HandlerCtx) (Response, error) {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+
+		buf = make([]byte, 2*len(buf))
+	}
+
+	goroutines, errs := gostackparse.Parse(bytes.NewReader(buf))
+	if len(errs) > 0 {
+		c.logger(log).Warn("parse goroutines error", map[string]interface{}{"_error": errs})
+	}
+
+	return NewResponseSuccess(ResponseBody{Data: goroutines}), nil
+}
+
+// handleMemStats respond runtime.MemStats, GC stats & number of goroutines
+func handleMemStats(c *HandlerCtx) (Response, error) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	var gc debug.GCStats
+	debug.ReadGCStats(&gc)
+
+	return NewResponseSuccess(ResponseBody{Data: keyvalue.KeyValue{
+		"mem_stats":  mem,
+		"gc_stats":   gc,
+		"goroutines": runtime.NumGoroutine(),
+	}}), nil
+}
@@ -0,0 +1,164 @@
+package noob
+
+import (
+	"encoding/json"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newDiagnosticsTestApp(t *testing.T, cfg ...DiagnosticsCfg) http.Handler {
+	t.Helper()
+
+	app := NewWithOptions(WithCfg(Cfg{RequestTimeout: 10 * time.Millisecond}))
+	app.MountDiagnostics(cfg...)
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	return h
+}
+
+func serveDiagnostics(h http.Handler, target string, remoteAddr string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.RemoteAddr = remoteAddr
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestDiagnosticsAllowLoopback(t *testing.T) {
+	h := newDiagnosticsTestApp(t)
+
+	// Forwarding headers must not bypass the guard
+	rec := serveDiagnostics(h, "/debug/pprof/", "203.0.113.1:1234", "X-Forwarded-For", "127.0.0.1")
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for remote client, got %d", rec.Code)
+	}
+
+	for _, addr := range []string{"127.0.0.1:1234", "[::1]:1234"} {
+		rec = serveDiagnostics(h, "/debug/pprof/", addr)
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Types of profiles available") {
+			t.Fatalf("expected pprof index for %s, got %d", addr, rec.Code)
+		}
+	}
+}
+
+func TestDiagnosticsGuard(t *testing.T) {
+	h := newDiagnosticsTestApp(t, DiagnosticsCfg{
+		Path: "/internal",
+		Guard: func(c *HandlerCtx) (Response, error) {
+			if c.GetHeader("X-Debug-Token") != "secret" {
+				return nil, DefaultForbiddenErrorResponse
+			}
+
+			return c.Next()
+		},
+	})
+
+	if rec := serveDiagnostics(h, "/internal/pprof/heap?debug=1", "127.0.0.1:1234"); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 without token, got %d", rec.Code)
+	}
+
+	rec := serveDiagnostics(h, "/internal/pprof/heap?debug=1", "203.0.113.1:1234", "X-Debug-Token", "secret")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "heap profile") {
+		t.Fatalf("expected heap profile, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestDiagnosticsGoroutines(t *testing.T) {
+	h := newDiagnosticsTestApp(t)
+
+	rec := serveDiagnostics(h, "/debug/goroutines", "127.0.0.1:1234")
+
+	var body struct {
+		Data []struct {
+			ID    int
+			State string
+			Stack []struct {
+				Func string
+			}
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body error: %v", err)
+	}
+
+	if len(body.Data) == 0 || body.Data[0].ID == 0 || body.Data[0].State == "" || len(body.Data[0].Stack) == 0 {
+		t.Fatalf("expected parsed goroutines, got %s", rec.Body.String())
+	}
+}
+
+func TestDiagnosticsMemStats(t *testing.T) {
+	h := newDiagnosticsTestApp(t)
+
+	rec := serveDiagnostics(h, "/debug/memstats", "127.0.0.1:1234")
+
+	var body struct {
+		Data struct {
+			MemStats struct {
+				HeapAlloc uint64
+			} `json:"mem_stats"`
+			Goroutines int `json:"goroutines"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body error: %v", err)
+	}
+
+	if body.Data.MemStats.HeapAlloc == 0 || body.Data.Goroutines == 0 {
+		t.Fatalf("expected memstats, got %s", rec.Body.String())
+	}
+}
+
+func TestDiagnosticsNoTimeout(t *testing.T) {
+	h := newDiagnosticsTestApp(t)
+
+	// Trace run longer than Cfg.RequestTimeout
+	rec := serveDiagnostics(h, "/debug/pprof/trace?seconds=0.05", "127.0.0.1:1234")
+	if rec.Code != http.StatusOK || rec.Body.Len() == 0 {
+		t.Fatalf("expected trace, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestDiagnosticsCustomPath(t *testing.T) {
+	h := newDiagnosticsTestApp(t, DiagnosticsCfg{Path: "/internal/diag"})
+
+	rec := serveDiagnostics(h, "/internal/diag/pprof/", "127.0.0.1:1234")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Types of profiles available") {
+		t.Fatalf("expected pprof index, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	// Every profile linked by the index is served under the custom path
+	links := regexp.MustCompile(`href=['"]([^'"]+)['"]`).FindAllStringSubmatch(rec.Body.String(), -1)
+	if len(links) == 0 {
+		t.Fatalf("expected profile links, got %s", rec.Body.String())
+	}
+
+	for _, m := range links {
+		link := html.UnescapeString(m[1])
+		if strings.HasPrefix(link, "http") || strings.HasPrefix(link, "profile") || strings.HasPrefix(link, "trace") {
+			// External docs & long running profiles
+			continue
+		}
+
+		if r := serveDiagnostics(h, "/internal/diag/pprof/"+link, "127.0.0.1:1234"); r.Code != http.StatusOK {
+			t.Fatalf("expected link %s served, got %d: %s", link, r.Code, r.Body.String())
+		}
+	}
+
+	if rec := serveDiagnostics(h, "/debug/pprof/", "127.0.0.1:1234"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected default path not mounted, got %d", rec.Code)
+	}
+}
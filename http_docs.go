@@ -0,0 +1,161 @@
+package noob
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"html/template"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+//go:embed assets/swagger-ui/*.js assets/swagger-ui/*.css assets/swagger-ui/*.png
+var swaggerUIAssets embed.FS
+
+const (
+	docsSpecFile   = "openapi.json"
+	docsAssetsDir  = "assets/swagger-ui"
+	docsAssetsPath = "/assets/*file"
+)
+
+// DocsCfg is configuration of API documentation mounted by Router.MountDocs
+type DocsCfg struct {
+	// Enable serve the documentation even if the application is not in debug mode
+	Enable bool
+
+	// Title of the documentation page. Default to "API Documentation"
+	Title string
+}
+
+var docsIndexTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="UTF-8">
+	<title>{{.Title}}</title>
+	<link rel="stylesheet" type="text/css" href="./assets/swagger-ui.css">
+	<link rel="icon" type="image/png" href="./assets/favicon-32x32.png" sizes="32x32">
+	<link rel="icon" type="image/png" href="./assets/favicon-16x16.png" sizes="16x16">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="./assets/swagger-ui-bundle.js" charset="UTF-8"></script>
+	<script>
+		window.onload = function () {
+			window.ui = SwaggerUIBundle({
+				url: "./{{.Spec}}",
+				dom_id: "#swagger-ui",
+				deepLinking: true,
+				presets: [SwaggerUIBundle.presets.apis]
+			});
+		};
+	</script>
+</body>
+</html>
+`))
+
+// docs serve Swagger UI & OpenAPI document
+type docs struct {
+	cfg  DocsCfg
+	spec []byte
+
+	specOnce sync.Once
+	specErr  error
+}
+
+// MountDocs serve Swagger UI & OpenAPI JSON document under path of the router, e.g. /docs/ of the application is served on Cfg.Path + /docs/.
+// If spec is nil, the document generated by Ctx.OpenAPI is served. Assets are embedded, so no external CDN is needed.
+// Documentation is served only when the application is in debug mode, unless DocsCfg.Enable is set
+func (e *Router) MountDocs(path string, spec []byte, cfg ...DocsCfg) {
+	d := &docs{
+		spec: spec,
+	}
+
+	if len(cfg) > 0 {
+		d.cfg = cfg[0]
+	}
+
+	if d.cfg.Title == "" {
+		d.cfg.Title = "API Documentation"
+	}
+
+	path = strings.TrimSuffix(path, "/")
+	hidden := RouteDoc{Hidden: true}
+
+	// Page use relative URLs, so it works under any base path. Path without trailing slash is redirected by the engine
+	e.GET(path+"/", d.handleIndex).Doc(hidden)
+	e.GET(path+"/"+docsSpecFile, d.handleSpec).Doc(hidden)
+	e.GET(path+docsAssetsPath, d.handleAsset).Doc(hidden)
+}
+
+func (d *docs) enabled(c *HandlerCtx) bool {
+	return d.cfg.Enable || c.isDebug()
+}
+
+func (d *docs) handleIndex(c *HandlerCtx) (Response, error) {
+	if !d.enabled(c) {
+		return nil, DefaultNotFoundErrorResponse
+	}
+
+	var b bytes.Buffer
+	if err := docsIndexTemplate.Execute(&b, map[string]string{
+		"Title": d.cfg.Title,
+		"Spec":  docsSpecFile,
+	}); err != nil {
+		return nil, err
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", b.Bytes())
+
+	return nil, nil
+}
+
+func (d *docs) handleSpec(c *HandlerCtx) (Response, error) {
+	if !d.enabled(c) {
+		return nil, DefaultNotFoundErrorResponse
+	}
+
+	// Routes are booted once, so the generated document never change
+	d.specOnce.Do(func() {
+		if d.spec != nil {
+			return
+		}
+
+		app := c.App()
+		if app == nil {
+			d.specErr = NewCoreError("docs: OpenAPI document can only be generated for routes served by Ctx")
+			return
+		}
+
+		d.spec, d.specErr = json.Marshal(app.OpenAPI())
+	})
+
+	if d.specErr != nil {
+		return nil, d.specErr
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", d.spec)
+
+	return nil, nil
+}
+
+func (d *docs) handleAsset(c *HandlerCtx) (Response, error) {
+	if !d.enabled(c) {
+		return nil, DefaultNotFoundErrorResponse
+	}
+
+	name := path.Clean(c.Param("file"))
+
+	b, err := fs.ReadFile(swaggerUIAssets, docsAssetsDir+name)
+	if err != nil {
+		return nil, DefaultNotFoundErrorResponse
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, mime.TypeByExtension(path.Ext(name)), b)
+
+	return nil, nil
+}
@@ -0,0 +1,89 @@
+package noob
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serveTest(t *testing.T, h http.Handler, path string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+	return rec
+}
+
+func TestMountDocs(t *testing.T) {
+	app := NewWithOptions(WithCfg(Cfg{Path: "/api"}), WithDebug(true))
+	app.GET("/users", routesHandler).Doc(RouteDoc{Summary: "List users"})
+	app.MountDocs("/docs", nil)
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	if rec := serveTest(t, h, "/api/docs"); rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "/api/docs/" {
+		t.Fatalf("expected redirect to /api/docs/, got %d %s", rec.Code, rec.Header().Get("Location"))
+	}
+
+	rec := serveTest(t, h, "/api/docs/")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `url: "./openapi.json"`) || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("unexpected index %d %s", rec.Code, rec.Body)
+	}
+
+	rec = serveTest(t, h, "/api/docs/openapi.json")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"/api/users"`) || strings.Contains(rec.Body.String(), "/api/docs") {
+		t.Fatalf("unexpected spec %d %s", rec.Code, rec.Body)
+	}
+
+	for _, asset := range []string{"swagger-ui-bundle.js", "swagger-ui.css", "favicon-32x32.png"} {
+		if rec := serveTest(t, h, "/api/docs/assets/"+asset); rec.Code != http.StatusOK || rec.Body.Len() == 0 {
+			t.Fatalf("unexpected asset %s %d", asset, rec.Code)
+		}
+	}
+
+	if rec := serveTest(t, h, "/api/docs/assets/../../go.mod"); rec.Code == http.StatusOK {
+		t.Fatal("expected file outside assets to be not found")
+	}
+}
+
+func TestMountDocsSuppliedSpec(t *testing.T) {
+	spec := []byte(`{"openapi":"3.0.3","info":{"title":"supplied","version":"1"},"paths":{}}`)
+
+	app := NewWithOptions(WithDebug(true))
+	app.MountDocs("/docs/", spec)
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	if rec := serveTest(t, h, "/docs/openapi.json"); rec.Code != http.StatusOK || rec.Body.String() != string(spec) {
+		t.Fatalf("unexpected spec %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestMountDocsDisabledWithoutDebug(t *testing.T) {
+	app := NewWithOptions(WithDebug(false))
+	app.MountDocs("/docs", nil)
+	app.MountDocs("/public-docs", nil, DocsCfg{Enable: true, Title: "Public API"})
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	for _, path := range []string{"/docs/", "/docs/openapi.json", "/docs/assets/swagger-ui.css"} {
+		if rec := serveTest(t, h, path); rec.Code != http.StatusNotFound {
+			t.Fatalf("expected %s to be disabled, got %d", path, rec.Code)
+		}
+	}
+
+	if rec := serveTest(t, h, "/public-docs/"); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "<title>Public API</title>") {
+		t.Fatalf("expected explicitly enabled docs, got %d %s", rec.Code, rec.Body)
+	}
+}
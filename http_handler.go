@@ -5,13 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"github.com/alfarih31/nb-go-http/utils"
+	keyvalue "github.com/alfarih31/nb-go-keyvalue"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
 	"runtime"
 )
 
 const extKeyErrors = "_errors"
 const extKeyPrevRes = "_prevRes"
 const extKeyPrevErr = "_prevErr"
+const extKeyApp = "_app"
 
 var errResponseAlreadyAborted = errors.New("response already aborted")
 
@@ -101,7 +105,7 @@ func (hc HandlerChain) compact(postHandlers ...HandlerChain) gin.HandlerFunc {
 			},
 			Catch: func(err interface{}, frames *runtime.Frames) {
 				// Send Error
-				logR.Warn("error caught! don't panic, use return error instead", map[string]interface{}{"_error": err})
+				c.logger(logR).Warn("error caught! don't panic, use return error instead", map[string]interface{}{"_error": err})
 
 				c.SendError(err, frames)
 			},
@@ -179,6 +183,11 @@ func (c *HandlerCtx) response(status *HTTPStatusCode, body *ResponseBody, header
 		return errResponseAlreadyAborted
 	}
 
+	// return if the request is timed out, the timeout response is already sent
+	if w, ok := c.Writer.(*timeoutWriter); ok && w.isTimedOut() {
+		return nil
+	}
+
 	// Set default headers
 	c.setHeader(&DefaultResponseHeader)
 
@@ -200,6 +209,28 @@ func (c *HandlerCtx) response(status *HTTPStatusCode, body *ResponseBody, header
 	return e
 }
 
+// responseStatus return status of the response. res & err returned by the next handlers are sent after the middlewares return,
+// so their status is used when the response is not written yet
+func (c *HandlerCtx) responseStatus(res Response, err error) int {
+	if c.Writer.Written() {
+		return c.Writer.Status()
+	}
+
+	if err != nil {
+		if r, ok := err.(ResponseError); ok && r.GetCode() != nil {
+			return int(*r.GetCode())
+		}
+
+		return int(StatusInternalServerError)
+	}
+
+	if res != nil && res.GetCode() != nil {
+		return int(*res.GetCode())
+	}
+
+	return c.Writer.Status()
+}
+
 func (c *HandlerCtx) StackError(e *CoreError) {
 	c.Keys[extKeyErrors] = append(c.Keys[extKeyErrors].(Errors), e)
 }
@@ -214,6 +245,16 @@ func (c *HandlerCtx) setHandlers(handlers HandlerChain) {
 }
 
 func (c *HandlerCtx) GetNext() HandlerFunc {
+	h := c.nextHandler()
+	if h == nil {
+		return nil
+	}
+
+	return h.fn
+}
+
+// nextHandler return next handler of the chain, or call next gin handlers & return nil at the end of the chain
+func (c *HandlerCtx) nextHandler() *Handler {
 	if c.handlerIdx >= len(c.handlers) || c.nextAborted {
 		c.Context.Next()
 
@@ -222,17 +263,21 @@ func (c *HandlerCtx) GetNext() HandlerFunc {
 
 	h := c.handlers[c.handlerIdx]
 	c.handlerIdx++
-	return h.fn
+	return &h
 }
 
 func (c *HandlerCtx) Next() (res Response, err error) {
-	h := c.GetNext()
+	h := c.nextHandler()
 
 	if h == nil {
 		return
 	}
 
-	return h(c)
+	if t, ok := c.Keys[extKeyTracing].(*tracing); ok && t.handlerSpans {
+		return t.handle(c, *h)
+	}
+
+	return h.fn(c)
 }
 
 func (c *HandlerCtx) Copy() *HandlerCtx {
@@ -243,13 +288,14 @@ func (c *HandlerCtx) Send(res Response) {
 	// Default send success
 	r := DefaultSuccessResponse.Copy()
 
-	// Compose success response to res
+	// Compose success response to copy of res, res may be shared e.g. DefaultSuccessResponse
+	res = res.Copy()
 	res.Compose(r)
 
 	rEr := c.response(res.GetCode(), res.GetBody(), res.GetHeader())
 
 	if rEr != nil {
-		logR.Error("send response error", map[string]interface{}{"_error": rEr})
+		c.logger(logR).Error("send response error", map[string]interface{}{"_error": rEr})
 	}
 }
 
@@ -258,19 +304,21 @@ func (c *HandlerCtx) SendError(e interface{}, frames *runtime.Frames) {
 
 	// Build Error
 	parsedErr := &CoreError{
-		Stack:  StackTrace(),
-		Frames: frames,
+		Stack:     StackTrace(),
+		Frames:    frames,
+		RequestID: c.RequestID(),
 	}
 
+	// Use copy of ResponseError, it may be shared e.g. DefaultNotFoundErrorResponse
 	switch er := e.(type) {
 	case ResponseError:
-		r = er
+		r = er.CopyError()
 		parsedErr.Err = er
 	case error:
 		// Try assertion type to Response
 		cR, ok := er.(ResponseError)
 		if ok {
-			r = cR
+			r = cR.CopyError()
 		}
 
 		parsedErr.Err = er
@@ -278,27 +326,92 @@ func (c *HandlerCtx) SendError(e interface{}, frames *runtime.Frames) {
 		parsedErr.Err = fmt.Errorf("%v", er)
 	}
 
-	// If debug then compose to body
-	if isDebug {
-		r.ComposeBody(ResponseBody{
-			Errors: parsedErr.JSON(),
-		})
-	} else {
-		r.ComposeBody(ResponseBody{
-			Errors: parsedErr.Error(),
-		})
+	// Keep errors of the response, e.g. FieldErrors of Bind, composing them fail when they are slice or map
+	body := ResponseBody{RequestID: parsedErr.RequestID}
+	if rb := r.GetBody(); rb == nil || rb.Errors == nil {
+		// If debug then compose to body
+		if c.isDebug() {
+			body.Errors = parsedErr.JSON()
+		} else {
+			body.Errors = parsedErr.Error()
+		}
 	}
 
+	r.ComposeBody(body)
+
 	// Stack Error to Context
 	c.StackError(parsedErr)
 
+	// Record on the current span, it is no-op if the request is not traced
+	trace.SpanFromContext(c.Request.Context()).RecordError(parsedErr.Err)
+
 	rEr := c.response(r.GetCode(), r.GetBody(), r.GetHeader())
 
 	if rEr != nil {
-		logR.Error("send response error", map[string]interface{}{"_error": rEr})
+		c.logger(logR).Error("send response error", map[string]interface{}{"_error": rEr})
 	}
 }
 
+// Push initiate HTTP/2 server push of target, return http.ErrNotSupported if the connection doesn't support push
+func (c *HandlerCtx) Push(target string, opts *http.PushOptions) error {
+	p := c.Writer.Pusher()
+	if p == nil {
+		return http.ErrNotSupported
+	}
+
+	return p.Push(target, opts)
+}
+
+// App return the application which serve the request, nil if the handler is not served by Ctx
+func (c *HandlerCtx) App() *Ctx {
+	if v, exist := c.Keys[extKeyApp]; exist {
+		if app, ok := v.(*Ctx); ok {
+			return app
+		}
+	}
+
+	return nil
+}
+
+// cfg return Cfg of the owning application, fallback to DefaultCfg
+func (c *HandlerCtx) cfg() Cfg {
+	if app := c.App(); app != nil {
+		return app.Cfg
+	}
+
+	return DefaultCfg
+}
+
+// corsCfg return CORSCfg of the route or the owning application, fallback to DefaultCORSCfg
+func (c *HandlerCtx) corsCfg() CORSCfg {
+	if o := c.routeOverrides(); o != nil && o.cors != nil {
+		return *o.cors
+	}
+
+	if app := c.App(); app != nil {
+		return app.CORSCfg
+	}
+
+	return DefaultCORSCfg
+}
+
+// meta return Meta of the owning application, fallback to DefaultMeta
+func (c *HandlerCtx) meta() keyvalue.KeyValue {
+	if app := c.App(); app != nil {
+		return app.Meta
+	}
+
+	return DefaultMeta
+}
+
+func (c *HandlerCtx) isDebug() bool {
+	if app := c.App(); app != nil {
+		return app.Debug
+	}
+
+	return isDebugEnv()
+}
+
 func (c *HandlerCtx) GetPrevResponse() (res Response) {
 	if v, exist := c.Keys[extKeyPrevRes]; exist {
 		if cv, ok := v.(Response); ok {
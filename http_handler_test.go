@@ -0,0 +1,99 @@
+package noob
+
+import (
+	"bytes"
+	"errors"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPushNotSupportedHTTP1(t *testing.T) {
+	pushErr := make(chan error, 1)
+
+	app := newTestApp(t, nil)
+	app.GET("/push", func(c *HandlerCtx) (Response, error) {
+		pushErr <- c.Push("/static/app.js", nil)
+		return DefaultSuccessResponse, nil
+	})
+	app.start(t)
+	defer app.stop(t)
+
+	res, err := http.Get(app.url("/push"))
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	res.Body.Close()
+
+	if err := <-pushErr; !errors.Is(err, http.ErrNotSupported) {
+		t.Fatalf("expected http.ErrNotSupported, got %v", err)
+	}
+}
+
+func TestPushHTTP2(t *testing.T) {
+	pushErr := make(chan error, 1)
+
+	app := newTestApp(t, func(cfg *Cfg) {
+		cfg.H2C = true
+	})
+	app.GET("/push", func(c *HandlerCtx) (Response, error) {
+		pushErr <- c.Push("/static/app.js", nil)
+		return DefaultSuccessResponse, nil
+	})
+	app.start(t)
+	defer app.stop(t)
+
+	conn, err := net.Dial("tcp", app.addr)
+	if err != nil {
+		t.Fatalf("dial error: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+
+	// Go HTTP/2 client disable push, so speak HTTP/2 with push enabled by hand
+	if _, err := io.WriteString(conn, http2.ClientPreface); err != nil {
+		t.Fatalf("write preface error: %v", err)
+	}
+
+	fr := http2.NewFramer(conn, conn)
+	if err := fr.WriteSettings(http2.Setting{ID: http2.SettingEnablePush, Val: 1}); err != nil {
+		t.Fatalf("write settings error: %v", err)
+	}
+
+	var hb bytes.Buffer
+	enc := hpack.NewEncoder(&hb)
+	for _, f := range [][2]string{{":method", "GET"}, {":scheme", "http"}, {":authority", app.addr}, {":path", "/push"}} {
+		_ = enc.WriteField(hpack.HeaderField{Name: f[0], Value: f[1]})
+	}
+
+	if err := fr.WriteHeaders(http2.HeadersFrameParam{StreamID: 1, BlockFragment: hb.Bytes(), EndStream: true, EndHeaders: true}); err != nil {
+		t.Fatalf("write headers error: %v", err)
+	}
+
+	for {
+		f, err := fr.ReadFrame()
+		if err != nil {
+			t.Fatalf("read frame error: %v", err)
+		}
+
+		switch f := f.(type) {
+		case *http2.SettingsFrame:
+			if !f.IsAck() {
+				_ = fr.WriteSettingsAck()
+			}
+		case *http2.PushPromiseFrame:
+			if err := <-pushErr; err != nil {
+				t.Fatalf("push error: %v", err)
+			}
+			return
+		case *http2.HeadersFrame:
+			t.Fatalf("expected push promise before response, push error: %v", <-pushErr)
+		case *http2.GoAwayFrame:
+			t.Fatalf("connection closed by server: %v", f.ErrCode)
+		}
+	}
+}
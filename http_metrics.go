@@ -0,0 +1,174 @@
+package noob
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DefaultMetricsPath is path of the metrics endpoint mounted by MountMetrics
+const DefaultMetricsPath = "/metrics"
+
+// DefaultMetricsBuckets is upper bounds in seconds of request duration histogram buckets, the same as Prometheus client default
+var DefaultMetricsBuckets = prometheus.DefBuckets
+
+// metricsUnmatchedRoute is route label of requests not matched by any route
+const metricsUnmatchedRoute = "unmatched"
+
+// extKeyRejected is reason the request is rejected by built-in middlewares, see rejectedThrottled & rejectedTimedOut
+const extKeyRejected = "_rejected"
+
+const rejectedThrottled = "throttled"
+const rejectedTimedOut = "timed_out"
+
+var metricsMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true, http.MethodPatch: true,
+	http.MethodDelete: true, http.MethodConnect: true, http.MethodOptions: true, http.MethodTrace: true,
+}
+
+// MetricsCfg is configuration of Metrics
+type MetricsCfg struct {
+	// Namespace is prefix of metric names, default to "noob"
+	Namespace string
+
+	// Buckets is upper bounds in seconds of request duration histogram buckets, default to DefaultMetricsBuckets
+	Buckets []float64
+
+	// Registerer register the collectors, e.g. prometheus.DefaultRegisterer to expose them with Go & process collectors.
+	// Default to a new prometheus.Registry
+	Registerer prometheus.Registerer
+
+	// Gatherer is gathered by ServeMetrics, default to Registerer if it is prometheus.Gatherer, else prometheus.DefaultGatherer
+	Gatherer prometheus.Gatherer
+}
+
+// Metrics record RED metrics of requests with Prometheus collectors: count & duration histogram labeled by method, route
+// pattern & status, in-flight gauge labeled by method & route. Requests rejected by throttling or timeout are counted separately.
+// Use WithMetrics to record & MountMetrics to expose them
+type Metrics struct {
+	requests  *prometheus.CounterVec
+	durations *prometheus.HistogramVec
+	inFlight  *prometheus.GaugeVec
+	throttled *prometheus.CounterVec
+	timedOut  *prometheus.CounterVec
+
+	handler http.Handler
+}
+
+// NewMetrics return Metrics configured by cfg, it panics if the collectors are already registered to MetricsCfg.Registerer
+func NewMetrics(cfg ...MetricsCfg) *Metrics {
+	var c MetricsCfg
+	if len(cfg) > 0 {
+		c = cfg[0]
+	}
+
+	if c.Namespace == "" {
+		c.Namespace = "noob"
+	}
+
+	if len(c.Buckets) == 0 {
+		c.Buckets = DefaultMetricsBuckets
+	}
+
+	buckets := append([]float64{}, c.Buckets...)
+	sort.Float64s(buckets)
+
+	if c.Registerer == nil {
+		c.Registerer = prometheus.NewRegistry()
+	}
+
+	if c.Gatherer == nil {
+		c.Gatherer = prometheus.DefaultGatherer
+		if g, ok := c.Registerer.(prometheus.Gatherer); ok {
+			c.Gatherer = g
+		}
+	}
+
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: c.Namespace,
+			Name:      "http_requests_total",
+			Help:      "Total number of HTTP requests.",
+		}, []string{"method", "route", "status"}),
+		durations: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: c.Namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of HTTP requests in seconds.",
+			Buckets:   buckets,
+		}, []string{"method", "route", "status"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: c.Namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "Number of HTTP requests being served.",
+		}, []string{"method", "route"}),
+		throttled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: c.Namespace,
+			Name:      "http_requests_throttled_total",
+			Help:      "Total number of HTTP requests rejected by throttling or rate limit.",
+		}, []string{"method", "route"}),
+		timedOut: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: c.Namespace,
+			Name:      "http_requests_timed_out_total",
+			Help:      "Total number of HTTP requests responded by request timeout.",
+		}, []string{"method", "route"}),
+		handler: promhttp.HandlerFor(c.Gatherer, promhttp.HandlerOpts{}),
+	}
+
+	c.Registerer.MustRegister(m.requests, m.durations, m.inFlight, m.throttled, m.timedOut)
+
+	return m
+}
+
+// Handle is middleware recording metrics of the request
+func (m *Metrics) Handle(c *HandlerCtx) (res Response, err error) {
+	route := c.FullPath()
+	if route == "" {
+		route = metricsUnmatchedRoute
+	}
+
+	method := c.Request.Method
+	if !metricsMethods[method] {
+		method = "OTHER"
+	}
+
+	inFlight := m.inFlight.WithLabelValues(method, route)
+	inFlight.Inc()
+
+	start := time.Now()
+	defer func() {
+		status := strconv.Itoa(c.responseStatus(res, err))
+
+		inFlight.Dec()
+		m.requests.WithLabelValues(method, route, status).Inc()
+		m.durations.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+
+		switch c.GetString(extKeyRejected) {
+		case rejectedThrottled:
+			m.throttled.WithLabelValues(method, route).Inc()
+		case rejectedTimedOut:
+			m.timedOut.WithLabelValues(method, route).Inc()
+		}
+	}()
+
+	return c.Next()
+}
+
+// ServeMetrics respond metrics of MetricsCfg.Gatherer in Prometheus exposition format
+func (m *Metrics) ServeMetrics(c *HandlerCtx) (Response, error) {
+	m.handler.ServeHTTP(c.Writer, c.Request)
+
+	return nil, nil
+}
+
+// MountMetrics register m.ServeMetrics on path of the router, default to DefaultMetricsPath
+func (e *Router) MountMetrics(m *Metrics, paths ...string) {
+	p := DefaultMetricsPath
+	if len(paths) > 0 && paths[0] != "" {
+		p = paths[0]
+	}
+
+	e.GET(p, m.ServeMetrics).Doc(RouteDoc{Hidden: true})
+}
@@ -0,0 +1,202 @@
+package noob
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// metricsTestContentType is Prometheus text exposition format
+const metricsTestContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+func newMetricsTestApp(t *testing.T, m *Metrics, opts ...Option) http.Handler {
+	t.Helper()
+
+	app := NewWithOptions(append([]Option{WithMetrics(m)}, opts...)...)
+	app.GET("/users/:id", routesHandler)
+	app.GET("/slow", sleepHandler(30*time.Millisecond))
+	app.MountMetrics(m)
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	return h
+}
+
+func serveMetrics(t *testing.T, h http.Handler) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DefaultMetricsPath, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != metricsTestContentType {
+		t.Fatalf("expected content type %q, got %q", metricsTestContentType, ct)
+	}
+
+	b, _ := io.ReadAll(rec.Body)
+
+	return string(b)
+}
+
+func expectMetricLines(t *testing.T, text string, lines ...string) {
+	t.Helper()
+
+	for _, l := range lines {
+		if !strings.Contains(text, "\n"+l+"\n") {
+			t.Fatalf("expected line %q, got:\n%s", l, text)
+		}
+	}
+}
+
+func TestMetricsRouteLabels(t *testing.T) {
+	m := NewMetrics()
+	h := newMetricsTestApp(t, m)
+
+	serveMethod(t, h, http.MethodGet, "/users/1")
+	serveMethod(t, h, http.MethodGet, "/users/2")
+	serveMethod(t, h, http.MethodGet, "/missing")
+	serveMethod(t, h, "PURGE", "/users/3")
+
+	text := serveMetrics(t, h)
+
+	expectMetricLines(t, text,
+		"# TYPE noob_http_requests_total counter",
+		`noob_http_requests_total{method="GET",route="/users/:id",status="200"} 2`,
+		`noob_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`noob_http_requests_total{method="OTHER",route="unmatched",status="405"} 1`,
+		`noob_http_requests_in_flight{method="GET",route="/users/:id"} 0`,
+	)
+
+	// Path values must not leak to labels
+	if strings.Contains(text, "/users/1") {
+		t.Fatalf("expected route pattern label, got:\n%s", text)
+	}
+}
+
+func TestMetricsHistogram(t *testing.T) {
+	m := NewMetrics(MetricsCfg{Namespace: "app", Buckets: []float64{1, .01}})
+	h := newMetricsTestApp(t, m)
+
+	serveMethod(t, h, http.MethodGet, "/slow")
+
+	text := serveMetrics(t, h)
+
+	expectMetricLines(t, text,
+		"# TYPE app_http_request_duration_seconds histogram",
+		`app_http_request_duration_seconds_bucket{method="GET",route="/slow",status="200",le="0.01"} 0`,
+		`app_http_request_duration_seconds_bucket{method="GET",route="/slow",status="200",le="1"} 1`,
+		`app_http_request_duration_seconds_bucket{method="GET",route="/slow",status="200",le="+Inf"} 1`,
+		`app_http_request_duration_seconds_count{method="GET",route="/slow",status="200"} 1`,
+	)
+
+	var sum float64
+	for _, l := range strings.Split(text, "\n") {
+		if strings.HasPrefix(l, `app_http_request_duration_seconds_sum{method="GET",route="/slow",status="200"} `) {
+			sum, _ = strconv.ParseFloat(l[strings.LastIndexByte(l, ' ')+1:], 64)
+		}
+	}
+
+	if sum < .03 || sum > 1 {
+		t.Fatalf("expected duration sum of the slow request, got %v", sum)
+	}
+}
+
+func TestMetricsInFlight(t *testing.T) {
+	m := NewMetrics()
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	app := NewWithOptions(WithMetrics(m))
+	app.GET("/block", func(c *HandlerCtx) (Response, error) {
+		close(entered)
+		<-release
+
+		return DefaultSuccessResponse, nil
+	})
+	app.MountMetrics(m)
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/block", nil))
+	}()
+
+	<-entered
+	expectMetricLines(t, serveMetrics(t, h), `noob_http_requests_in_flight{method="GET",route="/block"} 1`)
+
+	close(release)
+	<-done
+	expectMetricLines(t, serveMetrics(t, h), `noob_http_requests_in_flight{method="GET",route="/block"} 0`)
+}
+
+func TestMetricsThrottled(t *testing.T) {
+	m := NewMetrics()
+	h := newMetricsTestApp(t, m, WithThrottlingCfg(ThrottlingCfg{Enable: true, MaxEventPerSec: 1, MaxBurstSize: 1}))
+
+	serveMethod(t, h, http.MethodGet, "/users/1")
+	serveMethod(t, h, http.MethodGet, "/users/1")
+
+	throttled := testutil.ToFloat64(m.throttled.WithLabelValues("GET", "/users/:id"))
+	rejected := testutil.ToFloat64(m.requests.WithLabelValues("GET", "/users/:id", "429"))
+
+	if throttled != 1 || rejected != 1 {
+		t.Fatalf("expected 1 throttled request with status 429, got %v & %v", throttled, rejected)
+	}
+}
+
+func TestMetricsTimedOut(t *testing.T) {
+	m := NewMetrics()
+	h := newMetricsTestApp(t, m, WithCfg(Cfg{RequestTimeout: 10 * time.Millisecond}))
+
+	serveMethod(t, h, http.MethodGet, "/slow")
+
+	text := serveMetrics(t, h)
+
+	expectMetricLines(t, text,
+		`noob_http_requests_total{method="GET",route="/slow",status="408"} 1`,
+		`noob_http_requests_timed_out_total{method="GET",route="/slow"} 1`,
+	)
+}
+
+func TestMetricsRegisterer(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector())
+
+	m := NewMetrics(MetricsCfg{Registerer: reg})
+	h := newMetricsTestApp(t, m)
+
+	serveMethod(t, h, http.MethodGet, "/users/1")
+
+	// Metrics are exposed with collectors of the registry
+	text := serveMetrics(t, h)
+	expectMetricLines(t, text, `noob_http_requests_total{method="GET",route="/users/:id",status="200"} 1`)
+	if !strings.Contains(text, "\ngo_goroutines ") {
+		t.Fatalf("expected Go collector metrics, got:\n%s", text)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic of duplicate registration")
+		}
+	}()
+	NewMetrics(MetricsCfg{Registerer: reg})
+}
@@ -0,0 +1,483 @@
+package noob
+
+import (
+	"fmt"
+	"github.com/alfarih31/nb-go-http/utils"
+	"net/http"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OpenAPIVersion is version of OpenAPI specification of generated document
+const OpenAPIVersion = "3.0.3"
+
+const (
+	openAPIResponseBodySchema = "ResponseBody"
+	openAPISchemaRef          = "#/components/schemas/"
+	openAPIResponseRef        = "#/components/responses/"
+	openAPIContentType        = "application/json"
+)
+
+// RouteDoc is documentation metadata of a route, attached with Route.Doc
+type RouteDoc struct {
+	OperationID string
+	Summary     string
+	Description string
+	Tags        []string
+	Deprecated  bool
+
+	// Hidden exclude the route from generated document
+	Hidden bool
+
+	// Request is value of JSON request body type, e.g. CreateUserRequest{}
+	Request interface{}
+
+	// Queries is query parameters of the route, the same descriptors passed to QueryParser.GetQueries
+	Queries []Query
+
+	// Responses is value of `data` type in ResponseBody per status code, e.g. {StatusOK: User{}}. nil value means no data
+	Responses map[HTTPStatusCode]interface{}
+}
+
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// OpenAPIDocument is OpenAPI 3 document, encode it with encoding/json
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                       `json:"components"`
+}
+
+type OpenAPIComponents struct {
+	Schemas   map[string]*OpenAPISchema   `json:"schemas,omitempty"`
+	Responses map[string]*OpenAPIResponse `json:"responses,omitempty"`
+}
+
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+type OpenAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *OpenAPISchema `json:"schema"`
+}
+
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required,omitempty"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIMediaType struct {
+	Schema  *OpenAPISchema `json:"schema"`
+	Example interface{}    `json:"example,omitempty"`
+}
+
+// OpenAPIResponse is response object, or reference to response component if Ref is set
+type OpenAPIResponse struct {
+	Ref         string                      `json:"$ref,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPISchema is subset of OpenAPI schema object used by the generator
+type OpenAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Items                *OpenAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*OpenAPISchema `json:"properties,omitempty"`
+	AdditionalProperties *OpenAPISchema            `json:"additionalProperties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AllOf                []*OpenAPISchema          `json:"allOf,omitempty"`
+	Default              interface{}               `json:"default,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+}
+
+// openAPIErrorResponse is default error response documented as response component
+type openAPIErrorResponse struct {
+	name string
+	res  ResponseError
+	// always is true if every operation may respond the error, e.g. by common middlewares
+	always bool
+	// withPathParams is true if operations with path parameters may respond the error
+	withPathParams bool
+}
+
+var openAPIErrorResponses = []openAPIErrorResponse{
+	{"InternalServerError", DefaultInternalServerErrorResponse, true, true},
+	{"TooManyRequests", DefaultTooManyRequestsErrorResponse, true, true},
+	{"RequestTimeout", DefaultRequestTimeoutErrorResponse, true, true},
+	{"NotFound", DefaultNotFoundErrorResponse, false, true},
+	{"Forbidden", DefaultForbiddenErrorResponse, false, false},
+	{"MethodNotAllowed", DefaultMethodNotAllowedErrorResponse, false, false},
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// openAPIGenerator collect schemas of named types as components
+type openAPIGenerator struct {
+	schemas map[string]*OpenAPISchema
+	names   map[reflect.Type]string
+}
+
+func newOpenAPIGenerator() *openAPIGenerator {
+	g := &openAPIGenerator{
+		schemas: map[string]*OpenAPISchema{},
+		names:   map[reflect.Type]string{},
+	}
+
+	// ResponseBody is the envelope of every response, data & _error are described per operation
+	g.names[reflect.TypeOf(ResponseBody{})] = openAPIResponseBodySchema
+	g.schemas[openAPIResponseBodySchema] = &OpenAPISchema{
+		Type: "object",
+		Properties: map[string]*OpenAPISchema{
+			"code":    {Type: "integer"},
+			"message": {Type: "string"},
+			"data":    {},
+			"_error":  {},
+		},
+	}
+
+	return g
+}
+
+// OpenAPI generate OpenAPI 3 document of all routes registered to routers of the provider
+func (t *HTTPProviderCtx) OpenAPI(info OpenAPIInfo) *OpenAPIDocument {
+	g := newOpenAPIGenerator()
+
+	doc := &OpenAPIDocument{
+		OpenAPI: OpenAPIVersion,
+		Info:    info,
+		Paths:   map[string]map[string]*OpenAPIOperation{},
+	}
+
+	for _, r := range t.Routes() {
+		if r.Doc != nil && r.Doc.Hidden {
+			continue
+		}
+
+		p, params := openAPIPath(r.Path)
+		if doc.Paths[p] == nil {
+			doc.Paths[p] = map[string]*OpenAPIOperation{}
+		}
+
+		doc.Paths[p][strings.ToLower(r.Method)] = g.operation(r, params)
+	}
+
+	doc.Components = OpenAPIComponents{
+		Schemas:   g.schemas,
+		Responses: map[string]*OpenAPIResponse{},
+	}
+
+	for _, e := range openAPIErrorResponses {
+		doc.Components.Responses[e.name] = &OpenAPIResponse{
+			Description: http.StatusText(int(*e.res.GetCode())),
+			Content: map[string]OpenAPIMediaType{
+				openAPIContentType: {
+					Schema:  &OpenAPISchema{Ref: openAPISchemaRef + openAPIResponseBodySchema},
+					Example: e.res.GetBody(),
+				},
+			},
+		}
+	}
+
+	return doc
+}
+
+// openAPIPath convert gin path parameters to OpenAPI path template, e.g. /users/:id to /users/{id}
+func openAPIPath(p string) (string, []string) {
+	var params []string
+
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			params = append(params, s[1:])
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+
+	return strings.Join(segments, "/"), params
+}
+
+func (g *openAPIGenerator) operation(r RouteInfo, params []string) *OpenAPIOperation {
+	d := r.Doc
+	if d == nil {
+		d = &RouteDoc{}
+	}
+
+	op := &OpenAPIOperation{
+		OperationID: d.OperationID,
+		Summary:     d.Summary,
+		Description: d.Description,
+		Tags:        d.Tags,
+		Deprecated:  d.Deprecated,
+		Responses:   map[string]*OpenAPIResponse{},
+	}
+
+	for _, p := range params {
+		op.Parameters = append(op.Parameters, &OpenAPIParameter{
+			Name:     p,
+			In:       "path",
+			Required: true,
+			Schema:   &OpenAPISchema{Type: "string"},
+		})
+	}
+
+	for _, q := range d.Queries {
+		op.Parameters = append(op.Parameters, &OpenAPIParameter{
+			Name:     q.Key,
+			In:       "query",
+			Required: q.Required,
+			Schema:   querySchema(q),
+		})
+	}
+
+	if d.Request != nil {
+		op.RequestBody = &OpenAPIRequestBody{
+			Required: true,
+			Content: map[string]OpenAPIMediaType{
+				openAPIContentType: {Schema: g.schema(reflect.TypeOf(d.Request))},
+			},
+		}
+	}
+
+	if len(d.Responses) == 0 {
+		op.Responses[strconv.Itoa(int(StatusOK))] = g.response(StatusOK, nil)
+	}
+
+	for code, data := range d.Responses {
+		op.Responses[strconv.Itoa(int(code))] = g.response(code, data)
+	}
+
+	for _, e := range openAPIErrorResponses {
+		if !e.always && !(e.withPathParams && len(params) > 0) {
+			continue
+		}
+
+		code := strconv.Itoa(int(*e.res.GetCode()))
+		if _, exist := op.Responses[code]; !exist {
+			op.Responses[code] = &OpenAPIResponse{Ref: openAPIResponseRef + e.name}
+		}
+	}
+
+	return op
+}
+
+// response describe ResponseBody envelope with data of type of data
+func (g *openAPIGenerator) response(code HTTPStatusCode, data interface{}) *OpenAPIResponse {
+	res := &OpenAPIResponse{
+		Description: http.StatusText(int(code)),
+	}
+
+	if code == StatusNoContent {
+		return res
+	}
+
+	schema := &OpenAPISchema{Ref: openAPISchemaRef + openAPIResponseBodySchema}
+	if data != nil {
+		schema = &OpenAPISchema{
+			AllOf: []*OpenAPISchema{
+				schema,
+				{
+					Type:       "object",
+					Properties: map[string]*OpenAPISchema{"data": g.schema(reflect.TypeOf(data))},
+				},
+			},
+		}
+	}
+
+	res.Content = map[string]OpenAPIMediaType{
+		openAPIContentType: {Schema: schema},
+	}
+
+	return res
+}
+
+func querySchema(q Query) *OpenAPISchema {
+	var s *OpenAPISchema
+	switch q.Type {
+	case QueryValueTypeBool:
+		s = &OpenAPISchema{Type: "boolean"}
+	case QueryValueTypeInt, QueryValueTypeUint:
+		s = &OpenAPISchema{Type: "integer"}
+	case QueryValueTypeInt32, QueryValueTypeUint32:
+		s = &OpenAPISchema{Type: "integer", Format: "int32"}
+	case QueryValueTypeInt64, QueryValueTypeUint64:
+		s = &OpenAPISchema{Type: "integer", Format: "int64"}
+	case QueryValueTypeFloat32:
+		s = &OpenAPISchema{Type: "number", Format: "float"}
+	case QueryValueTypeFloat64:
+		s = &OpenAPISchema{Type: "number", Format: "double"}
+	case QueryValueTypeDuration:
+		s = &OpenAPISchema{Type: "string", Format: "duration"}
+	case QueryValueTypeTime:
+		s = &OpenAPISchema{Type: "string", Format: "date-time"}
+	case QueryValueTypeStrings:
+		s = &OpenAPISchema{Type: "array", Items: &OpenAPISchema{Type: "string"}}
+	case QueryValueTypeInts:
+		s = &OpenAPISchema{Type: "array", Items: &OpenAPISchema{Type: "integer"}}
+	case QueryValueTypeInt64s:
+		s = &OpenAPISchema{Type: "array", Items: &OpenAPISchema{Type: "integer", Format: "int64"}}
+	case QueryValueTypeFloat64s:
+		s = &OpenAPISchema{Type: "array", Items: &OpenAPISchema{Type: "number", Format: "double"}}
+	default:
+		s = &OpenAPISchema{Type: "string"}
+	}
+
+	s.Default = q.Default
+	if d, ok := q.Default.(*utils.Datetime); ok {
+		s.Default = d.ToString()
+	}
+
+	// Enum of slice is allowed values of the items
+	if s.Items != nil {
+		s.Items.Enum = q.Enum
+	} else {
+		s.Enum = q.Enum
+	}
+
+	return s
+}
+
+// schema return schema of t. Named struct is registered as schema component & referenced
+func (g *openAPIGenerator) schema(t reflect.Type) *OpenAPISchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &OpenAPISchema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &OpenAPISchema{Type: "boolean"}
+	case reflect.Int32, reflect.Uint32:
+		return &OpenAPISchema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &OpenAPISchema{Type: "integer", Format: "int64"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint, reflect.Uint8, reflect.Uint16:
+		return &OpenAPISchema{Type: "integer"}
+	case reflect.Float32:
+		return &OpenAPISchema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &OpenAPISchema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &OpenAPISchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		// []byte is encoded as base64 string
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &OpenAPISchema{Type: "string", Format: "byte"}
+		}
+
+		return &OpenAPISchema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &OpenAPISchema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+
+		name := g.name(t)
+		if _, exist := g.schemas[name]; !exist {
+			// Register before describing fields, so recursive type reference itself
+			s := &OpenAPISchema{}
+			g.schemas[name] = s
+			*s = *g.structSchema(t)
+		}
+
+		return &OpenAPISchema{Ref: openAPISchemaRef + name}
+	default:
+		return &OpenAPISchema{}
+	}
+}
+
+// name return unique component name of t
+func (g *openAPIGenerator) name(t reflect.Type) string {
+	if n, exist := g.names[t]; exist {
+		return n
+	}
+
+	n := t.Name()
+	if _, taken := g.schemas[n]; taken {
+		n = path.Base(t.PkgPath()) + "." + t.Name()
+	}
+
+	for i := 2; ; i++ {
+		if _, taken := g.schemas[n]; !taken {
+			break
+		}
+		n = fmt.Sprintf("%s.%s%d", path.Base(t.PkgPath()), t.Name(), i)
+	}
+
+	g.names[t] = n
+
+	return n
+}
+
+func (g *openAPIGenerator) structSchema(t reflect.Type) *OpenAPISchema {
+	s := &OpenAPISchema{
+		Type:       "object",
+		Properties: map[string]*OpenAPISchema{},
+	}
+
+	g.fields(t, s)
+
+	return s
+}
+
+// fields describe fields of struct t to s the same way encoding/json encode them, embedded structs are flattened
+func (g *openAPIGenerator) fields(t reflect.Type, s *OpenAPISchema) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			g.fields(ft, s)
+			continue
+		}
+
+		// Skip unexported
+		if f.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		s.Properties[name] = g.schema(f.Type)
+
+		if strings.Contains(f.Tag.Get("binding"), "required") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package noob
+
+import (
+	"encoding/json"
+	"github.com/alfarih31/nb-go-http/utils"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type openAPITestAudit struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type openAPITestUser struct {
+	openAPITestAudit
+	ID       int64              `json:"id"`
+	Name     string             `json:"name" binding:"required"`
+	Tags     []string           `json:"tags,omitempty"`
+	Manager  *openAPITestUser   `json:"manager,omitempty"`
+	Extra    map[string]float64 `json:"extra"`
+	Password string             `json:"-"`
+	internal string
+}
+
+func TestOpenAPI(t *testing.T) {
+	app := NewWithOptions(WithCfg(Cfg{Path: "/api"}), WithMeta(map[string]interface{}{
+		"app_name":    "users",
+		"app_version": "v1.2.3",
+	}))
+
+	users := app.Branch("/users")
+	users.GET("", routesHandler).Doc(RouteDoc{
+		Summary: "List users",
+		Tags:    []string{"users"},
+		Queries: []Query{
+			{Key: "limit", Type: QueryValueTypeInt, Default: 10},
+			{Key: "name", Type: QueryValueTypeString, Required: true},
+		},
+		Responses: map[HTTPStatusCode]interface{}{StatusOK: []openAPITestUser{}},
+	})
+	users.POST("", routesHandler).Doc(RouteDoc{
+		Request:   openAPITestUser{},
+		Responses: map[HTTPStatusCode]interface{}{StatusCreated: &openAPITestUser{}},
+	})
+	users.DELETE("/:id", routesHandler)
+	app.MountRoutes()
+
+	doc := app.OpenAPI()
+
+	if doc.OpenAPI != OpenAPIVersion || doc.Info.Title != "users" || doc.Info.Version != "v1.2.3" {
+		t.Fatalf("unexpected document header %+v %+v", doc.OpenAPI, doc.Info)
+	}
+
+	if _, exist := doc.Paths["/api"+DefaultRoutesPath]; exist {
+		t.Fatal("hidden route is documented")
+	}
+
+	list := doc.Paths["/api/users"]["get"]
+	if list == nil || list.Summary != "List users" || len(list.Parameters) != 2 {
+		t.Fatalf("unexpected list operation %+v", list)
+	}
+
+	if p := list.Parameters[0]; p.In != "query" || p.Schema.Type != "integer" || p.Schema.Default != 10 || p.Required {
+		t.Fatalf("unexpected query parameter %+v", p)
+	}
+
+	data := list.Responses["200"].Content[openAPIContentType].Schema.AllOf[1].Properties["data"]
+	if data.Type != "array" || data.Items.Ref != openAPISchemaRef+"openAPITestUser" {
+		t.Fatalf("unexpected data schema %+v", data)
+	}
+
+	// Default errors of common middlewares are documented for every operation
+	for _, code := range []string{"500", "429", "408"} {
+		if list.Responses[code] == nil || list.Responses[code].Ref == "" {
+			t.Fatalf("expected default error %s, got %+v", code, list.Responses)
+		}
+	}
+
+	create := doc.Paths["/api/users"]["post"]
+	if create.RequestBody == nil || create.RequestBody.Content[openAPIContentType].Schema.Ref != openAPISchemaRef+"openAPITestUser" || create.Responses["201"] == nil {
+		t.Fatalf("unexpected create operation %+v", create)
+	}
+
+	del := doc.Paths["/api/users/{id}"]["delete"]
+	if del == nil || len(del.Parameters) != 1 || del.Parameters[0].In != "path" || del.Responses["404"] == nil || del.Responses["200"] == nil {
+		t.Fatalf("unexpected delete operation %+v", del)
+	}
+
+	user := doc.Components.Schemas["openAPITestUser"]
+	var props []string
+	for k := range user.Properties {
+		props = append(props, k)
+	}
+
+	want := map[string]string{"created_at": "string", "id": "integer", "name": "string", "tags": "array", "manager": "", "extra": "object"}
+	if len(user.Properties) != len(want) {
+		t.Fatalf("unexpected properties %v", props)
+	}
+
+	for k, typ := range want {
+		if user.Properties[k] == nil || user.Properties[k].Type != typ {
+			t.Fatalf("unexpected property %s: %+v", k, user.Properties[k])
+		}
+	}
+
+	if user.Properties["manager"].Ref != openAPISchemaRef+"openAPITestUser" || !reflect.DeepEqual(user.Required, []string{"name"}) {
+		t.Fatalf("unexpected user schema %+v", user)
+	}
+
+	if doc.Components.Responses["NotFound"] == nil || doc.Components.Schemas[openAPIResponseBodySchema] == nil {
+		t.Fatalf("unexpected components %+v", doc.Components)
+	}
+
+	if _, err := json.Marshal(doc); err != nil {
+		t.Fatalf("encode error: %v", err)
+	}
+}
+
+func TestOpenAPIPath(t *testing.T) {
+	p, params := openAPIPath("/files/:bucket/*path")
+	if p != "/files/{bucket}/{path}" || !reflect.DeepEqual(params, []string{"bucket", "path"}) {
+		t.Fatalf("unexpected path %s %v", p, params)
+	}
+}
+
+func TestOpenAPIQuerySchema(t *testing.T) {
+	s := querySchema(Query{Key: "status", Type: QueryValueTypeStrings, Enum: []string{"open", "closed"}})
+	if s.Type != "array" || s.Items == nil || !reflect.DeepEqual(s.Items.Enum, []string{"open", "closed"}) {
+		t.Fatalf("unexpected schema %+v", s)
+	}
+
+	s = querySchema(Query{Key: "since", Type: QueryValueTypeTime, Required: true, Default: utils.NewDatetimeFromEpoch(0)})
+	if s.Format != "date-time" || s.Default == nil {
+		t.Fatalf("unexpected schema %+v", s)
+	}
+
+	if s = querySchema(Query{Key: "ratio", Type: QueryValueTypeFloat32}); s.Type != "number" || s.Format != "float" {
+		t.Fatalf("unexpected schema %+v", s)
+	}
+}
@@ -3,11 +3,16 @@ package noob
 import (
 	"github.com/gin-gonic/gin"
 	"net"
+	"net/http"
+	"sync"
 )
 
 type HTTPProviderCtx struct {
 	Engine     *gin.Engine
 	rootRouter *Router
+
+	bootOnce sync.Once
+	bootErr  error
 }
 
 func (t *HTTPProviderCtx) Router(path string) *Router {
@@ -18,14 +23,33 @@ func (t *HTTPProviderCtx) Router(path string) *Router {
 	return t.rootRouter.Branch(path)
 }
 
-func (t *HTTPProviderCtx) preRun() error {
-	baseRouter := t.Engine.Group(t.rootRouter.basePath)
-	if err := t.rootRouter.boot(baseRouter); err != nil {
+// boot register routers to engine. If only is set, handlers are registered only for those routers & their branches
+func (t *HTTPProviderCtx) boot(engine *gin.Engine, only ...*Router) error {
+	baseRouter := engine.Group(t.rootRouter.basePath)
+	if err := t.rootRouter.boot(baseRouter, newRouterSet(only)); err != nil {
 		return err
 	}
 	return nil
 }
 
+// preRun register routers to Engine once, so routes are never registered twice
+func (t *HTTPProviderCtx) preRun() error {
+	t.bootOnce.Do(func() {
+		t.bootErr = t.boot(t.Engine)
+	})
+
+	return t.bootErr
+}
+
+// Handler boot routers to Engine & return it as http.Handler, e.g. to serve with httptest or noobtest
+func (t *HTTPProviderCtx) Handler() (http.Handler, error) {
+	if err := t.preRun(); err != nil {
+		return nil, err
+	}
+
+	return t.Engine, nil
+}
+
 func (t *HTTPProviderCtx) Run(baseUrl string) error {
 	if err := t.preRun(); err != nil {
 		return err
@@ -35,19 +59,53 @@ func (t *HTTPProviderCtx) Run(baseUrl string) error {
 }
 
 func (t *HTTPProviderCtx) RunListener(listener net.Listener) error {
+	if err := t.preRun(); err != nil {
+		return err
+	}
+
 	return t.Engine.RunListener(listener)
 }
 
+func newEngine() *gin.Engine {
+	e := gin.New()
+	e.RedirectTrailingSlash = true
+
+	return e
+}
+
+// deriveEngine create engine with settings & global middlewares of Engine.
+// Trusted proxies, template delims & NoMethod handlers are not exposed by gin, so they are not carried to the derived engine
+func (t *HTTPProviderCtx) deriveEngine() *gin.Engine {
+	src := t.Engine
+
+	e := newEngine()
+	e.RedirectTrailingSlash = src.RedirectTrailingSlash
+	e.RedirectFixedPath = src.RedirectFixedPath
+	e.HandleMethodNotAllowed = src.HandleMethodNotAllowed
+	e.ForwardedByClientIP = src.ForwardedByClientIP
+	e.AppEngine = src.AppEngine
+	e.UseRawPath = src.UseRawPath
+	e.UnescapePathValues = src.UnescapePathValues
+	e.RemoveExtraSlash = src.RemoveExtraSlash
+	e.RemoteIPHeaders = src.RemoteIPHeaders
+	e.TrustedPlatform = src.TrustedPlatform
+	e.MaxMultipartMemory = src.MaxMultipartMemory
+	e.HTMLRender = src.HTMLRender
+	e.FuncMap = src.FuncMap
+	e.Use(src.Handlers...)
+
+	return e
+}
+
 func HTTP() *HTTPProviderCtx {
 	h := &HTTPProviderCtx{
-		Engine: gin.New(),
+		Engine: newEngine(),
 		rootRouter: &Router{
 			basePath:             "/",
 			mapParentMiddlewares: wareCheckers{},
 			mapParentPostwares:   wareCheckers{},
 		},
 	}
-	h.Engine.RedirectTrailingSlash = true
 
 	return h
 }
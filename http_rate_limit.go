@@ -0,0 +1,128 @@
+package noob
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Rate limit headers follow https://datatracker.ietf.org/doc/draft-ietf-httpapi-ratelimit-headers
+const RateLimitLimit = "RateLimit-Limit"
+const RateLimitRemaining = "RateLimit-Remaining"
+const RateLimitReset = "RateLimit-Reset"
+const RetryAfter = "Retry-After"
+
+// RateLimitKeyFunc return key of the client the request is counted to. Request with empty key is not limited
+type RateLimitKeyFunc func(c *HandlerCtx) string
+
+// RateLimitByClientIP key requests by HandlerCtx.ClientIP
+func RateLimitByClientIP(c *HandlerCtx) string {
+	return c.ClientIP()
+}
+
+// RateLimitByHeader key requests by value of header, e.g. an API key. Requests without the header are keyed by client IP
+func RateLimitByHeader(header string) RateLimitKeyFunc {
+	return func(c *HandlerCtx) string {
+		if v := c.GetHeader(header); v != "" {
+			return header + ":" + v
+		}
+
+		return RateLimitByClientIP(c)
+	}
+}
+
+// RateLimitCfg is configuration of keyed rate limiter. Every key has its own token bucket of MaxBurstSize tokens refilled at MaxEventPerSec
+type RateLimitCfg struct {
+	MaxEventPerSec int
+	MaxBurstSize   int
+
+	// Window enable sliding window of MaxBurstSize requests in Window instead of token bucket, MaxEventPerSec is not used
+	Window time.Duration
+
+	// Key of the request, default to RateLimitByClientIP
+	Key RateLimitKeyFunc
+
+	// Store keep the rate limit state, default to NewMemoryRateLimitStore(MaxKeys). Request is allowed when Store fail
+	Store RateLimitStore
+
+	// MaxKeys is max number of keys kept by the default Store, least recently used key is evicted first. Default to 10000
+	MaxKeys int
+}
+
+func (c RateLimitCfg) validate() Errors {
+	var errs Errors
+	if c.Window == 0 && c.MaxEventPerSec <= 0 {
+		errs = append(errs, newConfigError("RateLimitCfg.MaxEventPerSec", "must be positive"))
+	}
+
+	if c.MaxBurstSize <= 0 {
+		errs = append(errs, newConfigError("RateLimitCfg.MaxBurstSize", "must be positive"))
+	}
+
+	if c.Window < 0 {
+		errs = append(errs, newConfigError("RateLimitCfg.Window", "must not be negative"))
+	}
+
+	if c.MaxKeys < 0 {
+		errs = append(errs, newConfigError("RateLimitCfg.MaxKeys", "must not be negative"))
+	}
+
+	return errs
+}
+
+// HandleRateLimit return rate limiter handler limiting every client separately. Limited request is responded with
+// DefaultTooManyRequestsErrorResponse & Retry-After header, every response carry RateLimit-* headers. It panic if cfg is invalid
+func HandleRateLimit(cfg RateLimitCfg) HandlerFunc {
+	if errs := cfg.validate(); errs != nil {
+		panic(NewCoreError(fmt.Sprintf("HandleRateLimit: %v", errs)))
+	}
+
+	if cfg.Key == nil {
+		cfg.Key = RateLimitByClientIP
+	}
+
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryRateLimitStore(cfg.MaxKeys)
+	}
+
+	return func(c *HandlerCtx) (Response, error) {
+		key := cfg.Key(c)
+		if key == "" {
+			return c.Next()
+		}
+
+		r, err := cfg.take(c.Request.Context(), "ratelimit:"+key, time.Now())
+		if err != nil {
+			c.logger(log).Error(fmt.Sprintf("rate limit store error, %v", err))
+			return c.Next()
+		}
+
+		c.Header(RateLimitLimit, strconv.Itoa(r.Limit))
+		c.Header(RateLimitRemaining, strconv.Itoa(r.Remaining))
+		c.Header(RateLimitReset, ceilSeconds(r.Reset))
+
+		if !r.Allowed {
+			c.Header(RetryAfter, ceilSeconds(r.RetryAfter))
+			c.Set(extKeyRejected, rejectedThrottled)
+
+			return nil, DefaultTooManyRequestsErrorResponse
+		}
+
+		return c.Next()
+	}
+}
+
+func (c RateLimitCfg) take(ctx context.Context, key string, now time.Time) (RateLimitResult, error) {
+	if c.Window > 0 {
+		return c.Store.SlidingWindow(ctx, key, c.MaxBurstSize, c.Window, now)
+	}
+
+	return c.Store.TakeToken(ctx, key, float64(c.MaxEventPerSec), c.MaxBurstSize, now)
+}
+
+// ceilSeconds format d as whole seconds, rounded up
+func ceilSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
@@ -0,0 +1,176 @@
+package noob
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// RedisEvaler evaluate Lua script on Redis & return its reply, e.g. adapter of go-redis:
+//
+//	noob.RedisEvalFunc(func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+//		return rdb.Eval(ctx, script, keys, args...).Result()
+//	})
+type RedisEvaler interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// RedisEvalFunc is function implementing RedisEvaler
+type RedisEvalFunc func(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+
+func (f RedisEvalFunc) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	return f(ctx, script, keys, args...)
+}
+
+// redisTokenBucketScript refill & take a token from bucket hash of KEYS[1]. ARGV is rate, burst & now in milliseconds.
+// It return allowed flag & tokens left as string, as Lua number is truncated to integer reply
+const redisTokenBucketScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'last')
+local tokens = tonumber(bucket[1])
+local last = tonumber(bucket[2])
+if tokens == nil or last == nil then
+	tokens = burst
+	last = now
+end
+
+if now > last then
+	tokens = math.min(burst, tokens + (now - last) / 1000 * rate)
+	last = now
+end
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'last', tostring(last))
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1)
+
+return {allowed, tostring(tokens)}
+`
+
+// redisSlidingWindowScript count event to sorted set of KEYS[1] scored by time. ARGV is limit, window & now in milliseconds & unique
+// member of the event. It return allowed flag, count of events in window & score of the oldest & newest event
+const redisSlidingWindowScript = `
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+
+redis.call('PEXPIRE', KEYS[1], window)
+
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+local newest = redis.call('ZRANGE', KEYS[1], -1, -1, 'WITHSCORES')
+
+return {allowed, count, oldest[2] or '0', newest[2] or '0'}
+`
+
+// redisRateLimitStore is RateLimitStore keeping state on Redis, every operation is a single atomic script
+type redisRateLimitStore struct {
+	client RedisEvaler
+	prefix string
+
+	// seq make members of sliding window unique
+	seq uint64
+}
+
+// NewRedisRateLimitStore return RateLimitStore keeping state on Redis under keys prefixed by prefix, so the quota is shared by every
+// instance using the same Redis. Time is taken from the instances, so their clocks should be synchronized
+func NewRedisRateLimitStore(client RedisEvaler, prefix string) RateLimitStore {
+	return &redisRateLimitStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *redisRateLimitStore) TakeToken(ctx context.Context, key string, rate float64, burst int, now time.Time) (RateLimitResult, error) {
+	reply, err := s.client.Eval(ctx, redisTokenBucketScript, []string{s.prefix + key},
+		strconv.FormatFloat(rate, 'f', -1, 64), burst, now.UnixNano()/int64(time.Millisecond))
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	values, err := redisReply(reply, 2)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	tokens, err := strconv.ParseFloat(values[1], 64)
+	if err != nil {
+		return RateLimitResult{}, NewCoreError(fmt.Sprintf("redis rate limit store: invalid tokens %q", values[1]))
+	}
+
+	return tokenBucketResult(values[0] == "1", tokens, rate, burst), nil
+}
+
+func (s *redisRateLimitStore) SlidingWindow(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (RateLimitResult, error) {
+	ms := now.UnixNano() / int64(time.Millisecond)
+	member := fmt.Sprintf("%d-%d", ms, atomic.AddUint64(&s.seq, 1))
+
+	reply, err := s.client.Eval(ctx, redisSlidingWindowScript, []string{s.prefix + key},
+		limit, window.Milliseconds(), ms, member)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	values, err := redisReply(reply, 4)
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	// Score may be formatted as float
+	var n [3]int64
+	for i := range n {
+		f, err := strconv.ParseFloat(values[i+1], 64)
+		if err != nil {
+			return RateLimitResult{}, NewCoreError(fmt.Sprintf("redis rate limit store: invalid reply %v", values))
+		}
+		n[i] = int64(f)
+	}
+
+	msTime := func(ms int64) time.Time {
+		return time.Unix(0, ms*int64(time.Millisecond))
+	}
+
+	return slidingWindowResult(values[0] == "1", int(n[0]), limit, window, msTime(n[1]), msTime(n[2]), msTime(ms)), nil
+}
+
+// redisReply convert array reply of n integer or string elements to strings
+func redisReply(reply interface{}, n int) ([]string, error) {
+	arr, ok := reply.([]interface{})
+	if !ok || len(arr) != n {
+		return nil, NewCoreError(fmt.Sprintf("redis rate limit store: unexpected reply %v", reply))
+	}
+
+	values := make([]string, n)
+	for i, v := range arr {
+		switch v := v.(type) {
+		case int64:
+			values[i] = strconv.FormatInt(v, 10)
+		case string:
+			values[i] = v
+		case []byte:
+			values[i] = string(v)
+		default:
+			return nil, NewCoreError(fmt.Sprintf("redis rate limit store: unexpected reply element %v", v))
+		}
+	}
+
+	return values, nil
+}
@@ -0,0 +1,175 @@
+package noob
+
+import (
+	"container/list"
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+const defaultRateLimitMaxKeys = 10000
+
+// RateLimitResult is result of counting a request to a rate limit
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+
+	// Reset is duration until the quota is fully restored
+	Reset time.Duration
+
+	// RetryAfter is duration until the next request is allowed, 0 if allowed
+	RetryAfter time.Duration
+}
+
+// RateLimitStore keep rate limit state of keys. Store shared between instances, e.g. Redis, enforce the quota across all of them
+type RateLimitStore interface {
+	// TakeToken take a token at now from token bucket of key, which hold burst tokens refilled at rate tokens per second
+	TakeToken(ctx context.Context, key string, rate float64, burst int, now time.Time) (RateLimitResult, error)
+
+	// SlidingWindow count an event at now to sliding window of key, which allow limit events in window
+	SlidingWindow(ctx context.Context, key string, limit int, window time.Duration, now time.Time) (RateLimitResult, error)
+}
+
+// tokenBucketResult return RateLimitResult of token bucket left with tokens after taking
+func tokenBucketResult(allowed bool, tokens float64, rate float64, burst int) RateLimitResult {
+	r := RateLimitResult{
+		Allowed:   allowed,
+		Limit:     burst,
+		Remaining: int(tokens),
+		Reset:     refillDuration(float64(burst)-tokens, rate),
+	}
+
+	if !allowed {
+		r.RetryAfter = refillDuration(1-tokens, rate)
+	}
+
+	return r
+}
+
+// slidingWindowResult return RateLimitResult of sliding window holding count events, oldest & newest is time of its oldest & newest event
+func slidingWindowResult(allowed bool, count int, limit int, window time.Duration, oldest time.Time, newest time.Time, now time.Time) RateLimitResult {
+	r := RateLimitResult{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: limit - count,
+	}
+
+	if count > 0 {
+		r.Reset = newest.Add(window).Sub(now)
+	}
+
+	if !allowed {
+		r.RetryAfter = oldest.Add(window).Sub(now)
+	}
+
+	return r
+}
+
+// refillDuration return duration to refill tokens at rate
+func refillDuration(tokens float64, rate float64) time.Duration {
+	return time.Duration(tokens / rate * float64(time.Second))
+}
+
+type memoryRateLimitEntry struct {
+	key string
+
+	// tokens & last is state of token bucket
+	tokens float64
+	last   time.Time
+
+	// events is time of events in sliding window, oldest first
+	events []time.Time
+}
+
+// memoryRateLimitStore is in-memory RateLimitStore with LRU eviction
+type memoryRateLimitStore struct {
+	maxKeys int
+
+	mu    sync.Mutex
+	lru   *list.List
+	index map[string]*list.Element
+}
+
+// NewMemoryRateLimitStore return RateLimitStore keeping at most maxKeys keys in memory of this instance, least recently used key is
+// evicted first. maxKeys <= 0 default to 10000
+func NewMemoryRateLimitStore(maxKeys int) RateLimitStore {
+	if maxKeys <= 0 {
+		maxKeys = defaultRateLimitMaxKeys
+	}
+
+	return &memoryRateLimitStore{
+		maxKeys: maxKeys,
+		lru:     list.New(),
+		index:   map[string]*list.Element{},
+	}
+}
+
+func (s *memoryRateLimitStore) TakeToken(_ context.Context, key string, rate float64, burst int, now time.Time) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, created := s.get(key)
+	if created {
+		e.tokens, e.last = float64(burst), now
+	}
+
+	// Refill tokens elapsed since last take
+	if elapsed := now.Sub(e.last).Seconds(); elapsed > 0 {
+		e.tokens = math.Min(float64(burst), e.tokens+elapsed*rate)
+		e.last = now
+	}
+
+	allowed := e.tokens >= 1
+	if allowed {
+		e.tokens--
+	}
+
+	return tokenBucketResult(allowed, e.tokens, rate, burst), nil
+}
+
+func (s *memoryRateLimitStore) SlidingWindow(_ context.Context, key string, limit int, window time.Duration, now time.Time) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, _ := s.get(key)
+
+	// Drop events out of the window
+	i := 0
+	for i < len(e.events) && !e.events[i].After(now.Add(-window)) {
+		i++
+	}
+	e.events = e.events[i:]
+
+	allowed := len(e.events) < limit
+	if allowed {
+		e.events = append(e.events, now)
+	}
+
+	var oldest, newest time.Time
+	if len(e.events) > 0 {
+		oldest, newest = e.events[0], e.events[len(e.events)-1]
+	}
+
+	return slidingWindowResult(allowed, len(e.events), limit, window, oldest, newest, now), nil
+}
+
+// get return entry of key, a new entry is created & the least recently used entry is evicted when maxKeys is reached
+func (s *memoryRateLimitStore) get(key string) (*memoryRateLimitEntry, bool) {
+	if e, ok := s.index[key]; ok {
+		s.lru.MoveToFront(e)
+		return e.Value.(*memoryRateLimitEntry), false
+	}
+
+	if s.lru.Len() >= s.maxKeys {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.index, oldest.Value.(*memoryRateLimitEntry).key)
+	}
+
+	e := &memoryRateLimitEntry{key: key}
+	s.index[key] = s.lru.PushFront(e)
+
+	return e, true
+}
@@ -0,0 +1,272 @@
+package noob
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/alicebob/miniredis/v2"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// respClient is minimal Redis client evaluating scripts over RESP, enough to test the Redis store against miniredis
+type respClient struct {
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func newRespClient(t *testing.T, addr string) *respClient {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("dial redis error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return &respClient{conn: conn, r: bufio.NewReader(conn)}
+}
+
+func (c *respClient) Eval(_ context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	cmd := []string{"EVAL", script, strconv.Itoa(len(keys))}
+	cmd = append(cmd, keys...)
+	for _, a := range args {
+		cmd = append(cmd, fmt.Sprint(a))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(cmd))
+	for _, s := range cmd {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(s), s)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.conn.Write([]byte(b.String())); err != nil {
+		return nil, err
+	}
+
+	return c.read()
+}
+
+func (c *respClient) read() (interface{}, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, fmt.Errorf("redis: %s", line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, _ := strconv.Atoi(line[1:])
+		if n < 0 {
+			return nil, nil
+		}
+
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, _ := strconv.Atoi(line[1:])
+		arr := make([]interface{}, n)
+		for i := range arr {
+			if arr[i], err = c.read(); err != nil {
+				return nil, err
+			}
+		}
+		return arr, nil
+	}
+
+	return nil, fmt.Errorf("redis: unknown reply %q", line)
+}
+
+func newTestRedisStore(t *testing.T) RateLimitStore {
+	t.Helper()
+
+	s := miniredis.RunT(t)
+
+	return NewRedisRateLimitStore(newRespClient(t, s.Addr()), "test:")
+}
+
+func testRateLimitStores(t *testing.T, test func(t *testing.T, store RateLimitStore)) {
+	t.Run("memory", func(t *testing.T) {
+		test(t, NewMemoryRateLimitStore(0))
+	})
+
+	t.Run("redis", func(t *testing.T) {
+		test(t, newTestRedisStore(t))
+	})
+}
+
+func TestRateLimitStoreTakeToken(t *testing.T) {
+	testRateLimitStores(t, func(t *testing.T, store RateLimitStore) {
+		ctx := context.Background()
+		now := time.Unix(1700000000, 0)
+
+		for i, remaining := range []int{1, 0} {
+			r, err := store.TakeToken(ctx, "a", 2, 2, now)
+			if err != nil || !r.Allowed || r.Remaining != remaining || r.Limit != 2 {
+				t.Fatalf("take %d: expected allowed with %d remaining, got %+v %v", i, remaining, r, err)
+			}
+		}
+
+		r, _ := store.TakeToken(ctx, "a", 2, 2, now)
+		if r.Allowed || r.RetryAfter != 500*time.Millisecond || r.Reset != time.Second {
+			t.Fatalf("expected limited with retry after 500ms, got %+v", r)
+		}
+
+		// Other key has its own bucket
+		if r, _ := store.TakeToken(ctx, "b", 2, 2, now); !r.Allowed {
+			t.Fatalf("expected other key allowed, got %+v", r)
+		}
+
+		if r, _ := store.TakeToken(ctx, "a", 2, 2, now.Add(500*time.Millisecond)); !r.Allowed || r.Remaining != 0 {
+			t.Fatalf("expected refilled token, got %+v", r)
+		}
+
+		if r, _ := store.TakeToken(ctx, "a", 2, 2, now.Add(time.Hour)); !r.Allowed || r.Remaining != 1 {
+			t.Fatalf("expected bucket capped at burst, got %+v", r)
+		}
+	})
+}
+
+func TestRateLimitStoreSlidingWindow(t *testing.T) {
+	testRateLimitStores(t, func(t *testing.T, store RateLimitStore) {
+		ctx := context.Background()
+		now := time.Unix(1700000000, 0)
+
+		if r, _ := store.SlidingWindow(ctx, "a", 2, time.Minute, now); !r.Allowed || r.Remaining != 1 || r.Reset != time.Minute {
+			t.Fatalf("expected allowed, got %+v", r)
+		}
+
+		if r, _ := store.SlidingWindow(ctx, "a", 2, time.Minute, now.Add(10*time.Second)); !r.Allowed || r.Remaining != 0 {
+			t.Fatalf("expected allowed, got %+v", r)
+		}
+
+		r, err := store.SlidingWindow(ctx, "a", 2, time.Minute, now.Add(20*time.Second))
+		if err != nil || r.Allowed || r.RetryAfter != 40*time.Second || r.Reset != 50*time.Second {
+			t.Fatalf("expected limited until the oldest event leave the window, got %+v %v", r, err)
+		}
+
+		if r, _ := store.SlidingWindow(ctx, "a", 2, time.Minute, now.Add(time.Minute)); !r.Allowed || r.Remaining != 0 {
+			t.Fatalf("expected the oldest event left the window, got %+v", r)
+		}
+	})
+}
+
+func TestMemoryRateLimitStoreEviction(t *testing.T) {
+	store := NewMemoryRateLimitStore(2).(*memoryRateLimitStore)
+	ctx := context.Background()
+	now := time.Now()
+
+	store.TakeToken(ctx, "a", 1, 1, now)
+	store.TakeToken(ctx, "b", 1, 1, now)
+
+	// Use a, so b is the least recently used
+	store.TakeToken(ctx, "a", 1, 1, now)
+	store.TakeToken(ctx, "c", 1, 1, now)
+
+	if _, ok := store.index["b"]; ok || store.lru.Len() != 2 {
+		t.Fatalf("expected b evicted, got %d keys", store.lru.Len())
+	}
+
+	if r, _ := store.TakeToken(ctx, "a", 1, 1, now); r.Allowed {
+		t.Fatalf("expected a kept, got %+v", r)
+	}
+
+	if r, _ := store.TakeToken(ctx, "b", 1, 1, now); !r.Allowed {
+		t.Fatalf("expected evicted b to start with full bucket, got %+v", r)
+	}
+}
+
+func TestRateLimitSharedStore(t *testing.T) {
+	store := newTestRedisStore(t)
+
+	// Replicas of the same service share the quota
+	replicas := []http.Handler{
+		newRateLimitTestApp(t, RateLimitCfg{MaxEventPerSec: 1, MaxBurstSize: 2, Store: store}),
+		newRateLimitTestApp(t, RateLimitCfg{MaxEventPerSec: 1, MaxBurstSize: 2, Store: store}),
+	}
+
+	codes := []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests}
+	for i, code := range codes {
+		if rec := serveMethod(t, replicas[i%2], http.MethodGet, "/limited"); rec.Code != code {
+			t.Fatalf("request %d: expected %d, got %d", i, code, rec.Code)
+		}
+	}
+}
+
+func TestRateLimitWindow(t *testing.T) {
+	h := newRateLimitTestApp(t, RateLimitCfg{MaxBurstSize: 1, Window: time.Minute})
+
+	if rec := serveMethod(t, h, http.MethodGet, "/limited"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	rec := serveMethod(t, h, http.MethodGet, "/limited")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get(RetryAfter) != "60" {
+		t.Fatalf("expected 429 with retry after a minute, got %d %v", rec.Code, rec.Header())
+	}
+}
+
+func TestRateLimitStoreError(t *testing.T) {
+	failing := NewRedisRateLimitStore(RedisEvalFunc(func(context.Context, string, []string, ...interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("connection refused")
+	}), "")
+
+	h := newRateLimitTestApp(t, RateLimitCfg{MaxEventPerSec: 1, MaxBurstSize: 1, Store: failing})
+	for i := 0; i < 3; i++ {
+		if rec := serveMethod(t, h, http.MethodGet, "/limited"); rec.Code != http.StatusOK {
+			t.Fatalf("expected request allowed when store fail, got %d", rec.Code)
+		}
+	}
+}
+
+func TestThrottlingStore(t *testing.T) {
+	store := newTestRedisStore(t)
+
+	app := NewWithOptions(WithThrottlingCfg(ThrottlingCfg{Enable: true, MaxEventPerSec: 1, MaxBurstSize: 1, Store: store}))
+	app.GET("/home", routesHandler)
+
+	login := app.Branch("/login", WithThrottling(ThrottlingCfg{Enable: true, MaxEventPerSec: 1, MaxBurstSize: 2, Store: store}))
+	login.POST("/", routesHandler)
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	codes := map[string][]int{
+		"/home":   {http.StatusOK, http.StatusTooManyRequests},
+		"/login/": {http.StatusOK, http.StatusOK, http.StatusTooManyRequests},
+	}
+
+	for path, want := range codes {
+		method := http.MethodGet
+		if path == "/login/" {
+			method = http.MethodPost
+		}
+
+		for i, code := range want {
+			if rec := serveMethod(t, h, method, path); rec.Code != code {
+				t.Fatalf("%s request %d: expected %d, got %d", path, i, code, rec.Code)
+			}
+		}
+	}
+}
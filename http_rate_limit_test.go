@@ -0,0 +1,109 @@
+package noob
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newRateLimitTestApp(t *testing.T, cfg RateLimitCfg) http.Handler {
+	t.Helper()
+
+	app := NewWithOptions()
+	app.USE(HandleRateLimit(cfg))
+	app.GET("/limited", routesHandler)
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	return h
+}
+
+func TestHandleRateLimitByClientIP(t *testing.T) {
+	h := newRateLimitTestApp(t, RateLimitCfg{MaxEventPerSec: 1, MaxBurstSize: 2})
+
+	serve := func(ip string) *http.Response {
+		req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		req.RemoteAddr = ip + ":1234"
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		return rec.Result()
+	}
+
+	for i, remaining := range []string{"1", "0"} {
+		res := serve("10.0.0.1")
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i, res.StatusCode)
+		}
+
+		if res.Header.Get(RateLimitLimit) != "2" || res.Header.Get(RateLimitRemaining) != remaining {
+			t.Fatalf("request %d: unexpected rate limit headers %v", i, res.Header)
+		}
+	}
+
+	res := serve("10.0.0.1")
+	if res.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", res.StatusCode)
+	}
+
+	if res.Header.Get(RetryAfter) != "1" || res.Header.Get(RateLimitRemaining) != "0" || res.Header.Get(RateLimitReset) != "2" {
+		t.Fatalf("unexpected 429 headers %v", res.Header)
+	}
+
+	// Other client has its own bucket
+	if res := serve("10.0.0.2"); res.StatusCode != http.StatusOK {
+		t.Fatalf("expected other client not limited, got %d", res.StatusCode)
+	}
+}
+
+func TestHandleRateLimitByHeader(t *testing.T) {
+	h := newRateLimitTestApp(t, RateLimitCfg{MaxEventPerSec: 1, MaxBurstSize: 1, Key: RateLimitByHeader("X-API-Key")})
+
+	if rec := serveMethod(t, h, http.MethodGet, "/limited", "X-API-Key", "a"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	if rec := serveMethod(t, h, http.MethodGet, "/limited", "X-API-Key", "a"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rec.Code)
+	}
+
+	if rec := serveMethod(t, h, http.MethodGet, "/limited", "X-API-Key", "b"); rec.Code != http.StatusOK {
+		t.Fatalf("expected other key not limited, got %d", rec.Code)
+	}
+
+	// Request without the header is keyed by client IP
+	if rec := serveMethod(t, h, http.MethodGet, "/limited"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+}
+
+func TestHandleRateLimitCustomKey(t *testing.T) {
+	h := newRateLimitTestApp(t, RateLimitCfg{MaxEventPerSec: 1, MaxBurstSize: 1, Key: func(c *HandlerCtx) string {
+		return c.Query("user")
+	}})
+
+	for i := 0; i < 3; i++ {
+		if rec := serveMethod(t, h, http.MethodGet, "/limited"); rec.Code != http.StatusOK {
+			t.Fatalf("expected request with empty key not limited, got %d", rec.Code)
+		}
+	}
+
+	serveMethod(t, h, http.MethodGet, "/limited?user=a")
+	if rec := serveMethod(t, h, http.MethodGet, "/limited?user=a"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rec.Code)
+	}
+}
+
+func TestHandleRateLimitInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic on invalid config")
+		}
+	}()
+
+	HandleRateLimit(RateLimitCfg{MaxEventPerSec: 0, MaxBurstSize: 1})
+}
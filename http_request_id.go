@@ -0,0 +1,74 @@
+package noob
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// HeaderRequestID is header carrying ID of the request, read from the request & echoed in the response
+const HeaderRequestID = "X-Request-ID"
+
+const extKeyRequestID = "_requestId"
+
+// maxRequestIDLength is max length of request ID accepted from the client
+const maxRequestIDLength = 128
+
+type requestIDContextKey struct{}
+
+// HandleRequestID read request ID from HeaderRequestID or generate a UUID when it is missing or invalid. The ID is stored on
+// HandlerCtx & Request.Context(), echoed in the response header, added to log lines of the request & error bodies of SendError
+func HandleRequestID(c *HandlerCtx) (Response, error) {
+	id := c.GetHeader(HeaderRequestID)
+	if !validRequestID(id) {
+		id = newRequestID()
+	}
+
+	c.Set(extKeyRequestID, id)
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), requestIDContextKey{}, id))
+	c.Header(HeaderRequestID, id)
+
+	return c.Next()
+}
+
+// RequestID return ID of the request, empty if HandleRequestID is not used
+func (c *HandlerCtx) RequestID() string {
+	return c.GetString(extKeyRequestID)
+}
+
+// RequestIDFromContext return request ID stored by HandleRequestID in ctx, e.g. to propagate it to outgoing requests
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+
+	return id
+}
+
+// validRequestID return true if id is non-empty printable ASCII without space, so it is safe to be logged & echoed
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < '!' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newRequestID return random UUID version 4
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
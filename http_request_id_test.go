@@ -0,0 +1,157 @@
+package noob
+
+import (
+	"encoding/json"
+	"errors"
+	_logger "github.com/alfarih31/nb-go-logger"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// captureHook record log entries
+type captureHook struct {
+	mu      sync.Mutex
+	entries []*logrus.Entry
+}
+
+func (h *captureHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *captureHook) Fire(e *logrus.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries = append(h.entries, e)
+
+	return nil
+}
+
+// captureLogs replace the loggers with loggers recording to the returned hook until the test finish
+func captureLogs(t *testing.T) *captureHook {
+	h := new(captureHook)
+
+	l := _logger.New("core")
+	l.AddHook(h)
+	log.set(l)
+	logR.set(l.NewChild("response"))
+
+	t.Cleanup(restartLogger)
+
+	return h
+}
+
+func newRequestIDTestApp(t *testing.T) http.Handler {
+	t.Helper()
+
+	app := NewWithOptions()
+	app.GET("/id", func(c *HandlerCtx) (Response, error) {
+		c.Logger().Info("handling")
+
+		return NewResponseSuccess(ResponseBody{Data: []string{c.RequestID(), RequestIDFromContext(c.Request.Context())}}), nil
+	})
+	app.GET("/fail", func(c *HandlerCtx) (Response, error) {
+		return nil, errors.New("failed")
+	})
+	app.GET("/panic", func(c *HandlerCtx) (Response, error) {
+		panic("boom")
+	})
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	return h
+}
+
+func TestHandleRequestIDGenerate(t *testing.T) {
+	h := newRequestIDTestApp(t)
+
+	rec := serveMethod(t, h, http.MethodGet, "/id")
+
+	id := rec.Header().Get(HeaderRequestID)
+	if !uuidPattern.MatchString(id) {
+		t.Fatalf("expected generated UUID, got %q", id)
+	}
+
+	var body struct {
+		Data []string `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || len(body.Data) != 2 || body.Data[0] != id || body.Data[1] != id {
+		t.Fatalf("expected request ID on HandlerCtx & context, got %s", rec.Body.String())
+	}
+
+	if other := serveMethod(t, h, http.MethodGet, "/id").Header().Get(HeaderRequestID); other == id {
+		t.Fatalf("expected unique request ID, got %q twice", id)
+	}
+}
+
+func TestHandleRequestIDPropagate(t *testing.T) {
+	h := newRequestIDTestApp(t)
+
+	if rec := serveMethod(t, h, http.MethodGet, "/id", HeaderRequestID, "req-123"); rec.Header().Get(HeaderRequestID) != "req-123" {
+		t.Fatalf("expected request ID echoed, got %q", rec.Header().Get(HeaderRequestID))
+	}
+
+	for _, invalid := range []string{"has space", strings.Repeat("a", maxRequestIDLength+1), "line\nbreak"} {
+		if rec := serveMethod(t, h, http.MethodGet, "/id", HeaderRequestID, invalid); !uuidPattern.MatchString(rec.Header().Get(HeaderRequestID)) {
+			t.Fatalf("expected invalid request ID %q replaced, got %q", invalid, rec.Header().Get(HeaderRequestID))
+		}
+	}
+}
+
+func TestHandleRequestIDErrorBody(t *testing.T) {
+	h := newRequestIDTestApp(t)
+
+	for _, path := range []string{"/fail", "/panic", "/missing"} {
+		rec := serveMethod(t, h, http.MethodGet, path, HeaderRequestID, "req-"+path[1:])
+
+		var body ResponseBody
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: decode body error: %v", path, err)
+		}
+
+		if body.RequestID != "req-"+path[1:] {
+			t.Fatalf("%s: expected request ID in error body, got %s", path, rec.Body.String())
+		}
+	}
+}
+
+func TestHandleRequestIDLogs(t *testing.T) {
+	hook := captureLogs(t)
+	h := newRequestIDTestApp(t)
+
+	serveMethod(t, h, http.MethodGet, "/id", HeaderRequestID, "req-log")
+	serveMethod(t, h, http.MethodGet, "/panic", HeaderRequestID, "req-log")
+
+	var messages []string
+	for _, e := range hook.entries {
+		if e.Data["requestId"] != "req-log" {
+			t.Fatalf("expected request ID in log %q, got %v", e.Message, e.Data)
+		}
+
+		messages = append(messages, e.Message)
+	}
+
+	// Handler log, request logs & caught panic
+	if len(messages) != 4 {
+		t.Fatalf("expected 4 logs, got %q", messages)
+	}
+}
+
+func TestCoreErrorRequestID(t *testing.T) {
+	e := NewCoreError("failed")
+	e.RequestID = "req-1"
+
+	b, err := json.Marshal(e)
+	if err != nil || !strings.Contains(string(b), `"request_id":"req-1"`) {
+		t.Fatalf("expected request ID in JSON, got %s %v", b, err)
+	}
+}
@@ -0,0 +1,145 @@
+package noob
+
+import (
+	"fmt"
+	"golang.org/x/time/rate"
+	"net/http"
+	"time"
+)
+
+// RouteOption override configuration of built-in middlewares for a branch or a route, see Router.Branch & Route.With
+type RouteOption func(o *routeOverrides)
+
+// routeOverrides is configuration overriding application configuration for a subtree or a route. nil field is not overridden
+type routeOverrides struct {
+	timeout    *time.Duration
+	throttling *ThrottlingCfg
+	cors       *CORSCfg
+
+	// limiter is owned by the branch or route declaring throttling, so the subtree share it
+	limiter *rate.Limiter
+
+	// throttlingScope identify the branch or route declaring throttling, it is key of the subtree in ThrottlingCfg.Store
+	throttlingScope string
+}
+
+// WithTimeout override Cfg.RequestTimeout, 0 disable the timeout
+func WithTimeout(timeout time.Duration) RouteOption {
+	return func(o *routeOverrides) {
+		o.timeout = &timeout
+	}
+}
+
+// WithThrottling override ThrottlingCfg, cfg with Enable false disable throttling
+func WithThrottling(cfg ThrottlingCfg) RouteOption {
+	return func(o *routeOverrides) {
+		o.throttling = &cfg
+	}
+}
+
+// WithCORS override CORSCfg
+func WithCORS(cfg CORSCfg) RouteOption {
+	return func(o *routeOverrides) {
+		cfg = cfg.copy()
+		o.cors = &cfg
+	}
+}
+
+func newRouteOverrides(opts []RouteOption) *routeOverrides {
+	if len(opts) == 0 {
+		return nil
+	}
+
+	o := new(routeOverrides)
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if o.throttling != nil && o.throttling.Enable && o.throttling.Store == nil {
+		o.limiter = rate.NewLimiter(rate.Limit(o.throttling.MaxEventPerSec), o.throttling.MaxBurstSize)
+	}
+
+	return o
+}
+
+// merge return overrides of o applied over parent. scope identify the branch or route declaring o, empty keep scope of o
+func (o *routeOverrides) merge(parent *routeOverrides, scope string) *routeOverrides {
+	if o == nil {
+		return parent
+	}
+
+	var m routeOverrides
+	if parent != nil {
+		m = *parent
+	}
+
+	if o.timeout != nil {
+		m.timeout = o.timeout
+	}
+
+	if o.throttling != nil {
+		m.throttling = o.throttling
+		m.limiter = o.limiter
+		m.throttlingScope = o.throttlingScope
+		if scope != "" {
+			m.throttlingScope = scope
+		}
+	}
+
+	if o.cors != nil {
+		m.cors = o.cors
+	}
+
+	return &m
+}
+
+func (o *routeOverrides) validate(route string) Errors {
+	var errs Errors
+	if o.timeout != nil && *o.timeout < 0 {
+		errs = append(errs, newConfigError(route, "WithTimeout must not be negative"))
+	}
+
+	if o.throttling != nil {
+		for _, err := range o.throttling.validate() {
+			errs = append(errs, newConfigError(route, fmt.Sprintf("WithThrottling is invalid, %v", err)))
+		}
+	}
+
+	if o.cors != nil {
+		for _, err := range o.cors.validate() {
+			errs = append(errs, newConfigError(route, fmt.Sprintf("WithCORS is invalid, %v", err)))
+		}
+	}
+
+	return errs
+}
+
+// requestTimeout return timeout of the route serving the request, 0 if disabled
+func (c *HandlerCtx) requestTimeout() time.Duration {
+	if o := c.routeOverrides(); o != nil && o.timeout != nil {
+		return *o.timeout
+	}
+
+	return c.cfg().RequestTimeout
+}
+
+// routeOverrides return overrides of the route serving the request, nil if not overridden
+func (c *HandlerCtx) routeOverrides() *routeOverrides {
+	app := c.App()
+	if app == nil || len(app.routeOverrides) == 0 {
+		return nil
+	}
+
+	method, path := c.Request.Method, c.FullPath()
+
+	// Route is not matched for preflight & method not allowed requests, find the route the request is meant for
+	if path == "" {
+		if method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			method = c.GetHeader("Access-Control-Request-Method")
+		}
+
+		path = app.matcher.route(method, c.Request.URL.Path)
+	}
+
+	return app.routeOverrides[method+" "+path]
+}
@@ -0,0 +1,149 @@
+package noob
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sleepHandler(d time.Duration) HandlerFunc {
+	return func(c *HandlerCtx) (Response, error) {
+		time.Sleep(d)
+		return DefaultSuccessResponse, nil
+	}
+}
+
+func TestRouteOverrideTimeout(t *testing.T) {
+	timeoutHandler := func(c *HandlerCtx) (Response, error) {
+		return NewResponseSuccess(ResponseBody{Data: c.requestTimeout().String()}), nil
+	}
+
+	app := NewWithOptions(WithCfg(Cfg{RequestTimeout: 50 * time.Millisecond}))
+	app.GET("/default", timeoutHandler)
+	app.GET("/slow", sleepHandler(100*time.Millisecond))
+	app.POST("/upload", sleepHandler(100*time.Millisecond)).With(WithTimeout(time.Second))
+
+	files := app.Branch("/files", WithTimeout(time.Second))
+	files.GET("/download", sleepHandler(100*time.Millisecond))
+	files.GET("/timeout", timeoutHandler)
+	files.GET("/strict", timeoutHandler).With(WithTimeout(20 * time.Millisecond))
+	files.GET("/stream", sleepHandler(100*time.Millisecond)).With(WithTimeout(0))
+	files.GET("/slow", sleepHandler(100*time.Millisecond)).With(WithTimeout(20 * time.Millisecond))
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	for _, r := range []struct{ method, path string }{
+		{http.MethodPost, "/upload"},
+		{http.MethodGet, "/files/download"},
+		{http.MethodGet, "/files/stream"},
+	} {
+		if rec := serveMethod(t, h, r.method, r.path); rec.Code != http.StatusOK {
+			t.Fatalf("%s %s: expected overridden timeout to let the handler finish, got %d", r.method, r.path, rec.Code)
+		}
+	}
+
+	for _, path := range []string{"/slow", "/files/slow"} {
+		if rec := serveMethod(t, h, http.MethodGet, path); rec.Code != http.StatusRequestTimeout {
+			t.Fatalf("GET %s: expected 408, got %d", path, rec.Code)
+		}
+	}
+
+	for path, want := range map[string]string{
+		"/default":       "50ms",
+		"/files/timeout": "1s",
+		"/files/strict":  "20ms",
+	} {
+		rec := serveMethod(t, h, http.MethodGet, path)
+		if !strings.Contains(rec.Body.String(), `"data":"`+want+`"`) {
+			t.Fatalf("%s: expected timeout %s, got %s", path, want, rec.Body.String())
+		}
+	}
+}
+
+func TestRouteOverrideThrottling(t *testing.T) {
+	app := NewWithOptions(WithThrottlingCfg(ThrottlingCfg{Enable: true, MaxEventPerSec: 1000, MaxBurstSize: 1000}))
+	app.POST("/login", routesHandler).With(WithThrottling(ThrottlingCfg{Enable: true, MaxEventPerSec: 1, MaxBurstSize: 1}))
+	app.GET("/home", routesHandler)
+
+	internal := app.Branch("/internal", WithThrottling(ThrottlingCfg{Enable: false}))
+	internal.GET("/health", routesHandler)
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	if rec := serveMethod(t, h, http.MethodPost, "/login"); rec.Code != http.StatusOK {
+		t.Fatalf("expected first login to pass, got %d", rec.Code)
+	}
+
+	if rec := serveMethod(t, h, http.MethodPost, "/login"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected second login to be throttled, got %d", rec.Code)
+	}
+
+	for i := 0; i < 10; i++ {
+		if rec := serveMethod(t, h, http.MethodGet, "/home"); rec.Code != http.StatusOK {
+			t.Fatalf("expected other route to use application throttling, got %d", rec.Code)
+		}
+
+		if rec := serveMethod(t, h, http.MethodGet, "/internal/health"); rec.Code != http.StatusOK {
+			t.Fatalf("expected disabled throttling, got %d", rec.Code)
+		}
+	}
+}
+
+func TestRouteOverrideCORS(t *testing.T) {
+	app := NewWithOptions(WithCORSCfg(CORSCfg{Enable: false}))
+	app.GET("/private", routesHandler)
+
+	public := app.Branch("/public", WithCORS(CORSCfg{Enable: true, AllowOrigins: []string{"http://a.example"}, AllowMethods: "GET,POST"}))
+	public.GET("/items", routesHandler)
+	public.POST("/items/:id", routesHandler)
+	public.POST("/items/me", routesHandler).With(WithCORS(CORSCfg{Enable: false}))
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	if rec := serveMethod(t, h, http.MethodGet, "/public/items", "Origin", "http://a.example"); rec.Header().Get(CORSAllowOrigin) != "http://a.example" {
+		t.Fatalf("expected CORS header, got %v", rec.Header())
+	}
+
+	if rec := serveMethod(t, h, http.MethodGet, "/public/items", "Origin", "http://b.example"); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected forbidden origin, got %d", rec.Code)
+	}
+
+	if rec := serveMethod(t, h, http.MethodGet, "/private", "Origin", "http://a.example"); rec.Header().Get(CORSAllowOrigin) != "" {
+		t.Fatalf("expected no CORS header outside the branch, got %v", rec.Header())
+	}
+
+	// Preflight is not matched by gin, it is resolved to the route of the requested method
+	rec := serveMethod(t, h, http.MethodOptions, "/public/items/1", "Origin", "http://a.example", "Access-Control-Request-Method", "POST")
+	if rec.Code != http.StatusNoContent || rec.Header().Get(CORSAllowMethods) != "GET,POST" {
+		t.Fatalf("expected preflight answered by CORS, got %d %v", rec.Code, rec.Header())
+	}
+
+	// Static route take precedence over parameter route
+	rec = serveMethod(t, h, http.MethodOptions, "/public/items/me", "Origin", "http://a.example", "Access-Control-Request-Method", "POST")
+	if rec.Code != http.StatusNoContent || rec.Header().Get(CORSAllowMethods) != "" || rec.Header().Get("Allow") == "" {
+		t.Fatalf("expected OPTIONS answered without CORS, got %d %v", rec.Code, rec.Header())
+	}
+}
+
+func TestRouteOverrideInvalid(t *testing.T) {
+	app := NewWithOptions()
+	app.Branch("/api", WithTimeout(-time.Second)).GET("/x", routesHandler).With(WithThrottling(ThrottlingCfg{Enable: true}))
+
+	_, err := app.Handler()
+
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("expected 3 config errors, got %v", err)
+	}
+}
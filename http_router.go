@@ -19,11 +19,49 @@ const (
 	head
 )
 
+var httpMethodNames = map[httpMethod]string{
+	get:     "GET",
+	post:    "POST",
+	put:     "PUT",
+	del:     "DELETE",
+	patch:   "PATCH",
+	options: "OPTIONS",
+	head:    "HEAD",
+}
+
+// String return HTTP method name, e.g. GET
+func (m httpMethod) String() string {
+	return httpMethodNames[m]
+}
+
 // routerHandler is type for Router handler
 type routerHandler struct {
 	method       httpMethod
 	path         string
 	handlerChain HandlerChain
+	doc          *RouteDoc
+	overrides    *routeOverrides
+}
+
+// Route is a route registered to Router, used to attach metadata to the route
+type Route struct {
+	router *Router
+	index  int
+}
+
+// Doc attach documentation metadata to the route, used by OpenAPI generation
+func (r *Route) Doc(doc RouteDoc) *Route {
+	r.router.handlers[r.index].doc = &doc
+
+	return r
+}
+
+// With override configuration of built-in middlewares for the route, e.g. WithTimeout
+func (r *Route) With(opts ...RouteOption) *Route {
+	h := &r.router.handlers[r.index]
+	h.overrides = newRouteOverrides(opts).merge(h.overrides, "")
+
+	return r
 }
 
 type Router struct {
@@ -35,6 +73,7 @@ type Router struct {
 	mapParentMiddlewares wareCheckers
 	mapParentPostwares   wareCheckers
 	branches             []*Router
+	overrides            *routeOverrides
 }
 
 // Handlers return slice to routerHandler
@@ -42,8 +81,8 @@ func (e *Router) Handlers() []routerHandler {
 	return e.handlers
 }
 
-// Branch used for branching router path
-func (e *Router) Branch(path string) *Router {
+// Branch used for branching router path. opts override configuration of built-in middlewares for the branch & its subtree
+func (e *Router) Branch(path string, opts ...RouteOption) *Router {
 	pm := wareCheckers{}
 	for k, v := range e.mapParentMiddlewares {
 		pm[k] = v
@@ -58,66 +97,53 @@ func (e *Router) Branch(path string) *Router {
 		absPath:              fmt.Sprintf("%s%s", e.absPath, path),
 		mapParentMiddlewares: pm,
 		mapParentPostwares:   pp,
+		overrides:            newRouteOverrides(opts),
 	}
 
 	e.branches = append(e.branches, r)
 	return r
 }
 
-func (e *Router) GET(path string, handlersFunc ...HandlerFunc) {
+// handle register handlersFunc of method on path & return its Route
+func (e *Router) handle(method httpMethod, path string, handlersFunc []HandlerFunc) *Route {
 	e.handlers = append(e.handlers, routerHandler{
 		path:         path,
-		method:       get,
+		method:       method,
 		handlerChain: NewHandlerChain(handlersFunc),
 	})
+
+	return &Route{
+		router: e,
+		index:  len(e.handlers) - 1,
+	}
 }
 
-func (e *Router) POST(path string, handlersFunc ...HandlerFunc) {
-	e.handlers = append(e.handlers, routerHandler{
-		path:         path,
-		method:       post,
-		handlerChain: NewHandlerChain(handlersFunc),
-	})
+func (e *Router) GET(path string, handlersFunc ...HandlerFunc) *Route {
+	return e.handle(get, path, handlersFunc)
 }
 
-func (e *Router) PUT(path string, handlersFunc ...HandlerFunc) {
-	e.handlers = append(e.handlers, routerHandler{
-		path:         path,
-		method:       put,
-		handlerChain: NewHandlerChain(handlersFunc),
-	})
+func (e *Router) POST(path string, handlersFunc ...HandlerFunc) *Route {
+	return e.handle(post, path, handlersFunc)
 }
 
-func (e *Router) DELETE(path string, handlersFunc ...HandlerFunc) {
-	e.handlers = append(e.handlers, routerHandler{
-		path:         path,
-		method:       del,
-		handlerChain: NewHandlerChain(handlersFunc),
-	})
+func (e *Router) PUT(path string, handlersFunc ...HandlerFunc) *Route {
+	return e.handle(put, path, handlersFunc)
 }
 
-func (e *Router) PATCH(path string, handlersFunc ...HandlerFunc) {
-	e.handlers = append(e.handlers, routerHandler{
-		path:         path,
-		method:       patch,
-		handlerChain: NewHandlerChain(handlersFunc),
-	})
+func (e *Router) DELETE(path string, handlersFunc ...HandlerFunc) *Route {
+	return e.handle(del, path, handlersFunc)
 }
 
-func (e *Router) OPTIONS(path string, handlersFunc ...HandlerFunc) {
-	e.handlers = append(e.handlers, routerHandler{
-		path:         path,
-		method:       options,
-		handlerChain: NewHandlerChain(handlersFunc),
-	})
+func (e *Router) PATCH(path string, handlersFunc ...HandlerFunc) *Route {
+	return e.handle(patch, path, handlersFunc)
 }
 
-func (e *Router) HEAD(path string, handlersFunc ...HandlerFunc) {
-	e.handlers = append(e.handlers, routerHandler{
-		path:         path,
-		method:       head,
-		handlerChain: NewHandlerChain(handlersFunc),
-	})
+func (e *Router) OPTIONS(path string, handlersFunc ...HandlerFunc) *Route {
+	return e.handle(options, path, handlersFunc)
+}
+
+func (e *Router) HEAD(path string, handlersFunc ...HandlerFunc) *Route {
+	return e.handle(head, path, handlersFunc)
 }
 
 func (e *Router) USE(handlersFunc ...HandlerFunc) {
@@ -129,57 +155,94 @@ func (e *Router) POSTUSE(handlersFunc ...HandlerFunc) {
 	e.postwares = append(e.postwares, NewHandlerChain(handlersFunc)...)
 }
 
-func (e *Router) boot(parentRouter *gin.RouterGroup) error {
-	baseRouter := parentRouter.Group(e.basePath)
+// routerSet is set of Router
+type routerSet map[*Router]bool
+
+func newRouterSet(routers []*Router) routerSet {
+	if len(routers) == 0 {
+		return nil
+	}
+
+	s := routerSet{}
+	for _, r := range routers {
+		s[r] = true
+	}
+
+	return s
+}
+
+// filteredWares return middlewares & postwares of router, excluding those already used by its parents, to prevent same wares invoke twice
+func (e *Router) filteredWares() (middlewares HandlerChain, postwares HandlerChain) {
+	mapMiddlewares := wareCheckers{}
+	for k, v := range e.mapParentMiddlewares {
+		mapMiddlewares[k] = v
+	}
+
+	mapPostwares := wareCheckers{}
+	for k, v := range e.mapParentPostwares {
+		mapPostwares[k] = v
+	}
 
-	// Filter middlewares to prevent same middlewares invoke twice
-	var (
-		filteredMiddlewares, filteredPostwares HandlerChain
-	)
 	for _, m := range e.middlewares {
 		// Get middleware name
 		mName := m.String()
-		if _, exist := e.mapParentMiddlewares[mName]; !exist {
-			filteredMiddlewares = append(filteredMiddlewares, m)
-			e.mapParentMiddlewares[mName] = true
+		if _, exist := mapMiddlewares[mName]; !exist {
+			middlewares = append(middlewares, m)
+			mapMiddlewares[mName] = true
 		}
 	}
 
 	for _, m := range e.postwares {
 		// Get postware name
 		mName := m.String()
-		if _, exist := e.mapParentPostwares[mName]; !exist {
-			filteredPostwares = append(filteredPostwares, m)
-			e.mapParentPostwares[mName] = true
+		if _, exist := mapPostwares[mName]; !exist {
+			postwares = append(postwares, m)
+			mapPostwares[mName] = true
 		}
 	}
 
+	return middlewares, postwares
+}
+
+// boot register handlers of router & its branches to parentRouter. If only is not nil, handlers are registered only for routers in only & their branches
+func (e *Router) boot(parentRouter *gin.RouterGroup, only routerSet) error {
+	baseRouter := parentRouter.Group(e.basePath)
+
+	// Router selected, register all of its subtree
+	if only[e] {
+		only = nil
+	}
+
+	filteredMiddlewares, filteredPostwares := e.filteredWares()
+
 	// put middlewares
 	if filteredMiddlewares != nil {
 		baseRouter.Use(filteredMiddlewares.compact())
 	}
 
-	for _, h := range e.handlers {
-		switch h.method {
-		case get:
-			baseRouter.GET(h.path, h.handlerChain.compact(filteredPostwares))
-		case post:
-			baseRouter.POST(h.path, h.handlerChain.compact(filteredPostwares))
-		case put:
-			baseRouter.PUT(h.path, h.handlerChain.compact(filteredPostwares))
-		case del:
-			baseRouter.DELETE(h.path, h.handlerChain.compact(filteredPostwares))
-		case patch:
-			baseRouter.PATCH(h.path, h.handlerChain.compact(filteredPostwares))
-		case options:
-			baseRouter.OPTIONS(h.path, h.handlerChain.compact(filteredPostwares))
-		case head:
-			baseRouter.HEAD(h.path, h.handlerChain.compact(filteredPostwares))
+	if only == nil {
+		for _, h := range e.handlers {
+			switch h.method {
+			case get:
+				baseRouter.GET(h.path, h.handlerChain.compact(filteredPostwares))
+			case post:
+				baseRouter.POST(h.path, h.handlerChain.compact(filteredPostwares))
+			case put:
+				baseRouter.PUT(h.path, h.handlerChain.compact(filteredPostwares))
+			case del:
+				baseRouter.DELETE(h.path, h.handlerChain.compact(filteredPostwares))
+			case patch:
+				baseRouter.PATCH(h.path, h.handlerChain.compact(filteredPostwares))
+			case options:
+				baseRouter.OPTIONS(h.path, h.handlerChain.compact(filteredPostwares))
+			case head:
+				baseRouter.HEAD(h.path, h.handlerChain.compact(filteredPostwares))
+			}
 		}
 	}
 
 	for _, b := range e.branches {
-		err := b.boot(baseRouter)
+		err := b.boot(baseRouter, only)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,217 @@
+package noob
+
+import (
+	"github.com/gin-gonic/gin"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultRoutesPath is path of the route table endpoint mounted by MountRoutes
+const DefaultRoutesPath = "/__routes"
+
+// RouteInfo describe a route registered in the Router tree
+type RouteInfo struct {
+	Method      string   `json:"method"`
+	Path        string   `json:"path"`
+	Handlers    []string `json:"handlers"`
+	Middlewares []string `json:"middlewares"`
+	Postwares   []string `json:"postwares"`
+
+	// Doc is documentation metadata attached with Route.Doc, nil if not documented
+	Doc *RouteDoc `json:"-"`
+
+	// overrides is resolved overrides of the route
+	overrides *routeOverrides
+}
+
+// joinPaths join relative path to absolute path the same way gin join group paths
+func joinPaths(absolutePath string, relativePath string) string {
+	if relativePath == "" {
+		return absolutePath
+	}
+
+	finalPath := path.Join(absolutePath, relativePath)
+	if relativePath[len(relativePath)-1] == '/' && finalPath[len(finalPath)-1] != '/' {
+		return finalPath + "/"
+	}
+
+	return finalPath
+}
+
+// routes return routes of router & its branches. absPath, middlewares & overrides are of its parent
+func (e *Router) routes(absPath string, middlewares HandlerChain, overrides *routeOverrides) []RouteInfo {
+	absPath = joinPaths(absPath, e.basePath)
+	overrides = e.overrides.merge(overrides, absPath)
+
+	filteredMiddlewares, filteredPostwares := e.filteredWares()
+	middlewares = append(append(HandlerChain{}, middlewares...), filteredMiddlewares...)
+
+	var routes []RouteInfo
+	for _, h := range e.handlers {
+		p := joinPaths(absPath, h.path)
+		routes = append(routes, RouteInfo{
+			Method:      h.method.String(),
+			Path:        p,
+			Handlers:    h.handlerChain.Strings(),
+			Middlewares: middlewares.Strings(),
+			Postwares:   filteredPostwares.Strings(),
+			Doc:         h.doc,
+			overrides:   h.overrides.merge(overrides, h.method.String()+" "+p),
+		})
+	}
+
+	for _, b := range e.branches {
+		routes = append(routes, b.routes(absPath, middlewares, overrides)...)
+	}
+
+	return routes
+}
+
+// Routes return all routes registered to routers of the provider
+func (t *HTTPProviderCtx) Routes() []RouteInfo {
+	return t.rootRouter.routes("/", nil, nil)
+}
+
+// HandleRoutes respond route table of the application as JSON. It respond not found when the application is not in debug mode
+func HandleRoutes(c *HandlerCtx) (Response, error) {
+	app := c.App()
+	if app == nil || !c.isDebug() {
+		return nil, DefaultNotFoundErrorResponse
+	}
+
+	return NewResponseSuccess(ResponseBody{
+		Data: app.Routes(),
+	}), nil
+}
+
+// MountRoutes register HandleRoutes on path of the router, default to DefaultRoutesPath
+func (e *Router) MountRoutes(paths ...string) {
+	p := DefaultRoutesPath
+	if len(paths) > 0 && paths[0] != "" {
+		p = paths[0]
+	}
+
+	e.GET(p, HandleRoutes).Doc(RouteDoc{Hidden: true})
+}
+
+// routeMatcher match request path to methods of routes registered to engine
+type routeMatcher struct {
+	engine *gin.Engine
+	once   sync.Once
+	routes []routePattern
+}
+
+type routePattern struct {
+	method   string
+	path     string
+	segments []string
+}
+
+func newRouteMatcher(engine *gin.Engine) *routeMatcher {
+	return &routeMatcher{engine: engine}
+}
+
+// methods return sorted methods of routes matching path
+func (m *routeMatcher) methods(p string) []string {
+	m.load()
+
+	segments := strings.Split(p, "/")
+
+	set := map[string]bool{}
+	for _, r := range m.routes {
+		if r.match(segments) {
+			set[r.method] = true
+		}
+	}
+
+	methods := make([]string, 0, len(set))
+	for method := range set {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	return methods
+}
+
+// route return path of route of method matching p, static segments take precedence like gin. Empty if not found
+func (m *routeMatcher) route(method string, p string) string {
+	m.load()
+
+	segments := strings.Split(p, "/")
+
+	var found *routePattern
+	for i, r := range m.routes {
+		if r.method == method && r.match(segments) && (found == nil || r.precede(*found)) {
+			found = &m.routes[i]
+		}
+	}
+
+	if found == nil {
+		return ""
+	}
+
+	return found.path
+}
+
+// load read routes of the engine once, after the engine is booted
+func (m *routeMatcher) load() {
+	m.once.Do(func() {
+		for _, r := range m.engine.Routes() {
+			m.routes = append(m.routes, routePattern{
+				method:   r.Method,
+				path:     r.Path,
+				segments: strings.Split(r.Path, "/"),
+			})
+		}
+	})
+}
+
+// precede return true if r take precedence over other at the first differing segment: static, then parameter, then catch-all
+func (r routePattern) precede(other routePattern) bool {
+	rank := func(s string) int {
+		switch {
+		case strings.HasPrefix(s, "*"):
+			return 2
+		case strings.HasPrefix(s, ":"):
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	for i := 0; i < len(r.segments) && i < len(other.segments); i++ {
+		if a, b := rank(r.segments[i]), rank(other.segments[i]); a != b {
+			return a < b
+		}
+	}
+
+	return false
+}
+
+// match return true if path segments match the pattern, following gin path parameter & catch-all syntax
+func (r routePattern) match(segments []string) bool {
+	for i, s := range r.segments {
+		if strings.HasPrefix(s, "*") {
+			return len(segments) >= i
+		}
+
+		if i >= len(segments) {
+			return false
+		}
+
+		if strings.HasPrefix(s, ":") {
+			if segments[i] == "" {
+				return false
+			}
+			continue
+		}
+
+		if s != segments[i] {
+			return false
+		}
+	}
+
+	return len(segments) == len(r.segments)
+}
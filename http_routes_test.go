@@ -0,0 +1,121 @@
+package noob
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func routesHandler(c *HandlerCtx) (Response, error) {
+	return DefaultSuccessResponse, nil
+}
+
+func routesMiddleware(c *HandlerCtx) (Response, error) {
+	return c.Next()
+}
+
+func routesPostware(c *HandlerCtx) (Response, error) {
+	return c.Next()
+}
+
+func TestRoutes(t *testing.T) {
+	app := NewWithOptions(WithCfg(Cfg{Path: "/api"}))
+	app.USE(routesMiddleware)
+
+	v1 := app.Branch("/v1")
+	v1.POSTUSE(routesPostware)
+	v1.GET("/users/:id", routesHandler)
+	v1.Branch("/admin/").DELETE("", routesHandler)
+
+	app.Provider.Router("/internal").HEAD("/health", routesHandler)
+
+	routes := map[string]RouteInfo{}
+	for _, r := range app.Routes() {
+		routes[r.Method+" "+r.Path] = r
+	}
+
+	if len(routes) != 3 {
+		t.Fatalf("expected 3 routes, got %+v", routes)
+	}
+
+	r, exist := routes["GET /api/v1/users/:id"]
+	if !exist {
+		t.Fatalf("GET /api/v1/users/:id not found in %+v", routes)
+	}
+
+	if len(r.Handlers) != 1 || len(r.Middlewares) != 1 || len(r.Postwares) != 1 {
+		t.Fatalf("unexpected route info %+v", r)
+	}
+
+	if _, exist := routes["DELETE /api/v1/admin/"]; !exist {
+		t.Fatalf("DELETE /api/v1/admin/ not found in %+v", routes)
+	}
+
+	if r := routes["HEAD /internal/health"]; len(r.Middlewares) != 0 {
+		t.Fatalf("unexpected middlewares of other router %+v", r)
+	}
+}
+
+func TestRoutesMatchBootedEngine(t *testing.T) {
+	app := NewWithOptions()
+	app.Branch("/a").Branch("/b/").GET("c", routesHandler)
+	app.Branch("/x").PUT("/", routesHandler)
+
+	if _, err := app.Handler(); err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	booted := map[string]bool{}
+	for _, r := range app.Provider.Engine.Routes() {
+		booted[r.Method+" "+r.Path] = true
+	}
+
+	for _, r := range app.Routes() {
+		if !booted[r.Method+" "+r.Path] {
+			t.Fatalf("route %s %s is not registered to engine %v", r.Method, r.Path, booted)
+		}
+	}
+}
+
+func TestMountRoutes(t *testing.T) {
+	app := NewWithOptions(WithDebug(true))
+	app.MountRoutes()
+	app.GET("/ping", routesHandler)
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DefaultRoutesPath, nil))
+
+	var body struct {
+		Data []RouteInfo `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	if rec.Code != http.StatusOK || len(body.Data) != 3 {
+		t.Fatalf("expected 3 routes, got %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestMountRoutesNotDebug(t *testing.T) {
+	app := NewWithOptions(WithDebug(false))
+	app.MountRoutes("/routes")
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/routes", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 when not debugging, got %d", rec.Code)
+	}
+}
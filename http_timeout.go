@@ -0,0 +1,268 @@
+package noob
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// timeoutGracePeriod is time handlers may take to return after the deadline before they are logged as ignoring it
+const timeoutGracePeriod = 100 * time.Millisecond
+
+// HandleTimeout respond DefaultRequestTimeoutErrorResponse when the next handlers don't finish in Cfg.RequestTimeout or the timeout of
+// the route. The deadline is set on Request.Context(), so handlers should stop when it is done.
+//
+// The next handlers run on the request goroutine & their response is buffered. When the timeout fire first, the timeout response is
+// sent immediately & later writes are discarded. Handlers that keep running after the timeout are logged
+func HandleTimeout(c *HandlerCtx) (Response, error) {
+	timeout := c.requestTimeout()
+	if timeout <= 0 {
+		return c.Next()
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	req, w := c.Request, c.Writer
+	tw := newTimeoutWriter(w)
+	id := c.RequestID()
+
+	c.Request = req.WithContext(ctx)
+	c.Writer = tw
+
+	done := make(chan struct{})
+	watched := make(chan struct{})
+	stopped := false
+	stopWatch := func() {
+		if !stopped {
+			stopped = true
+			close(done)
+			<-watched
+		}
+	}
+
+	// Restore when the handlers panic too, so response of the recovery is written to w instead of the buffer
+	defer func() {
+		stopWatch()
+		c.Request, c.Writer = req, w
+	}()
+
+	// Watch the deadline, the watcher only touch tw so it never race with the handlers on the context
+	go func() {
+		defer close(watched)
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				tw.timeout(DefaultRequestTimeoutErrorResponse, id)
+			}
+		}
+	}()
+
+	start := time.Now()
+	res, err := c.Next()
+
+	stopWatch()
+	c.Request, c.Writer = req, w
+
+	if elapsed := time.Since(start); elapsed > timeout+timeoutGracePeriod {
+		c.logger(logR).Warn("handler ignored request timeout", map[string]interface{}{
+			"method":  req.Method,
+			"path":    req.URL.Path,
+			"timeout": timeout.String(),
+			"elapsed": elapsed.String(),
+		})
+	}
+
+	if tw.timedOut || ctx.Err() == context.DeadlineExceeded {
+		c.Set(extKeyRejected, rejectedTimedOut)
+	}
+
+	if tw.timedOut {
+		// Timeout response is already sent, drop the result of the handlers
+		c.Abort()
+		c.nextAborted = true
+
+		return nil, nil
+	}
+
+	// Handlers finished at the deadline before the watcher, respond the timeout here
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, DefaultRequestTimeoutErrorResponse
+	}
+
+	if err := tw.flush(); err != nil {
+		c.logger(log).Error(err)
+	}
+
+	return res, err
+}
+
+var _ gin.ResponseWriter = new(timeoutWriter)
+
+// timeoutWriter buffer response of handlers until they finish, so the response is written by either the handlers or the timeout
+type timeoutWriter struct {
+	gin.ResponseWriter
+
+	mu       sync.Mutex
+	header   http.Header
+	body     bytes.Buffer
+	status   int
+	written  bool
+	timedOut bool
+}
+
+func newTimeoutWriter(w gin.ResponseWriter) *timeoutWriter {
+	return &timeoutWriter{
+		ResponseWriter: w,
+		header:         w.Header().Clone(),
+		status:         http.StatusOK,
+	}
+}
+
+func (t *timeoutWriter) Header() http.Header {
+	return t.header
+}
+
+func (t *timeoutWriter) WriteHeader(code int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if code > 0 && !t.written {
+		t.status = code
+	}
+}
+
+func (t *timeoutWriter) WriteHeaderNow() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.written = true
+}
+
+func (t *timeoutWriter) Write(b []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+
+	t.written = true
+
+	return t.body.Write(b)
+}
+
+func (t *timeoutWriter) WriteString(s string) (int, error) {
+	return t.Write([]byte(s))
+}
+
+func (t *timeoutWriter) Status() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.status
+}
+
+func (t *timeoutWriter) Size() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !t.written {
+		return -1
+	}
+
+	return t.body.Len()
+}
+
+func (t *timeoutWriter) isTimedOut() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.timedOut
+}
+
+func (t *timeoutWriter) Written() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.written
+}
+
+// Flush is no-op, the response is buffered until the handlers finish
+func (t *timeoutWriter) Flush() {}
+
+// Hijack is not supported, use WithTimeout(0) to disable the timeout of routes hijacking the connection, e.g. WebSocket
+func (t *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, http.ErrNotSupported
+}
+
+func (t *timeoutWriter) Pusher() http.Pusher {
+	return nil
+}
+
+// timeout write res with request ID to the underlying writer & discard the buffered response
+func (t *timeoutWriter) timeout(res Response, requestID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.timedOut = true
+
+	w := t.ResponseWriter
+	for k, v := range DefaultResponseHeader {
+		w.Header()[k] = v
+	}
+
+	if h := res.GetHeader(); h != nil {
+		for k, v := range *h {
+			w.Header()[k] = v
+		}
+	}
+
+	w.WriteHeader(int(*res.GetCode()))
+
+	body := *res.GetBody()
+	body.RequestID = requestID
+	if b, err := json.Marshal(body); err == nil {
+		_, _ = w.Write(b)
+	}
+	w.Flush()
+}
+
+// flush write buffered response to the underlying writer
+func (t *timeoutWriter) flush() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	w := t.ResponseWriter
+	dst := w.Header()
+	for k := range dst {
+		if _, ok := t.header[k]; !ok {
+			delete(dst, k)
+		}
+	}
+
+	for k, v := range t.header {
+		dst[k] = v
+	}
+
+	w.WriteHeader(t.status)
+
+	// Nothing written, keep the response open for the previous handlers
+	if !t.written {
+		return nil
+	}
+
+	w.WriteHeaderNow()
+
+	_, err := w.Write(t.body.Bytes())
+
+	return err
+}
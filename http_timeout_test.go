@@ -0,0 +1,186 @@
+package noob
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTimeoutTestApp(t *testing.T, timeout time.Duration, register func(app *Ctx)) http.Handler {
+	t.Helper()
+
+	app := NewWithOptions(WithCfg(Cfg{RequestTimeout: timeout}))
+	register(app)
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	return h
+}
+
+func TestHandleTimeoutRespond(t *testing.T) {
+	release := make(chan struct{})
+	returned := make(chan error, 1)
+
+	h := newTimeoutTestApp(t, 20*time.Millisecond, func(app *Ctx) {
+		// Handler ignoring cancellation, its response must be discarded
+		app.GET("/stuck", func(c *HandlerCtx) (Response, error) {
+			<-release
+
+			_, err := c.Writer.WriteString("late")
+			returned <- err
+
+			return DefaultSuccessResponse, nil
+		})
+	})
+
+	res := make(chan *httptest.ResponseRecorder)
+	go func() {
+		res <- serveMethod(t, h, http.MethodGet, "/stuck")
+	}()
+
+	// Timeout response is written while the handler is still running
+	time.Sleep(200 * time.Millisecond)
+	close(release)
+
+	if err := <-returned; err != http.ErrHandlerTimeout {
+		t.Fatalf("expected write after timeout to fail, got %v", err)
+	}
+
+	rec := <-res
+
+	var body ResponseBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body error: %v, body: %s", err, rec.Body.String())
+	}
+
+	if rec.Code != http.StatusRequestTimeout || body.Code != statusCodeErrRequestTimeout {
+		t.Fatalf("expected timeout response, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandleTimeoutDeadline(t *testing.T) {
+	h := newTimeoutTestApp(t, 20*time.Millisecond, func(app *Ctx) {
+		app.GET("/cooperative", func(c *HandlerCtx) (Response, error) {
+			if _, ok := c.Request.Context().Deadline(); !ok {
+				return nil, DefaultInternalServerErrorResponse
+			}
+
+			select {
+			case <-c.Request.Context().Done():
+				return nil, c.Request.Context().Err()
+			case <-time.After(time.Second):
+				return DefaultSuccessResponse, nil
+			}
+		})
+	})
+
+	start := time.Now()
+	if rec := serveMethod(t, h, http.MethodGet, "/cooperative"); rec.Code != http.StatusRequestTimeout {
+		t.Fatalf("expected 408, got %d", rec.Code)
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("expected handler cancelled at the deadline, took %v", elapsed)
+	}
+}
+
+func TestHandleTimeoutFinished(t *testing.T) {
+	h := newTimeoutTestApp(t, time.Second, func(app *Ctx) {
+		app.POST("/created", func(c *HandlerCtx) (Response, error) {
+			return NewResponse(StatusCreated, ResponseBody{Message: "created"}, ResponseHeader{"X-Resource": {"1"}}), nil
+		})
+
+		app.GET("/raw", func(c *HandlerCtx) (Response, error) {
+			c.String(http.StatusAccepted, "raw")
+			return nil, nil
+		})
+	})
+
+	rec := serveMethod(t, h, http.MethodPost, "/created", "Origin", "http://a.example")
+	if rec.Code != http.StatusCreated || rec.Header().Get("X-Resource") != "1" || rec.Header().Get(CORSAllowOrigin) == "" {
+		t.Fatalf("expected buffered response flushed with headers, got %d %v", rec.Code, rec.Header())
+	}
+
+	if rec = serveMethod(t, h, http.MethodGet, "/raw"); rec.Code != http.StatusAccepted || rec.Body.String() != "raw" {
+		t.Fatalf("expected raw response, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandleTimeoutRace(t *testing.T) {
+	app := newTestApp(t, func(cfg *Cfg) {
+		cfg.RequestTimeout = 5 * time.Millisecond
+	})
+	app.GET("/race/:n", func(c *HandlerCtx) (Response, error) {
+		// Keep using the context around the deadline
+		for i := 0; i < 10; i++ {
+			c.Header("X-Step", c.Param("n"))
+			c.Set("step", i)
+			time.Sleep(time.Millisecond)
+		}
+
+		return NewResponseSuccess(ResponseBody{Data: c.Param("n")}), nil
+	})
+	app.start(t)
+	defer app.stop(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			res, err := http.Get(app.url(fmt.Sprintf("/race/%d", i)))
+			if err != nil {
+				t.Errorf("request error: %v", err)
+				return
+			}
+			res.Body.Close()
+
+			if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusRequestTimeout {
+				t.Errorf("unexpected status %d", res.StatusCode)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestHandleTimeoutPanicRestore(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ec, _ := gin.CreateTestContext(rec)
+	ec.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	ec.Set(extKeyApp, NewWithOptions(WithCfg(Cfg{RequestTimeout: time.Second})))
+
+	c := WrapHandlerCtx(ec)
+	c.setHandlers(HandlerChain{{fn: func(c *HandlerCtx) (Response, error) {
+		panic("boom")
+	}}})
+
+	w, ctx := c.Writer, c.Request.Context()
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic of the handler")
+			}
+		}()
+
+		_, _ = HandleTimeout(c)
+	}()
+
+	// Recovery must write to the original writer without the deadline
+	if c.Writer != w || c.Request.Context() != ctx {
+		t.Fatal("expected writer & request restored after panic")
+	}
+
+	c.String(http.StatusInternalServerError, "recovered")
+	if rec.Code != http.StatusInternalServerError || rec.Body.String() != "recovered" {
+		t.Fatalf("expected recovery response, got %d %q", rec.Code, rec.Body.String())
+	}
+}
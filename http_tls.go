@@ -0,0 +1,162 @@
+package noob
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// TLSCfg is configuration for serving HTTPS. TLS is enabled when Config or CertFile is set
+type TLSCfg struct {
+	// CertFile & KeyFile is path to PEM encoded certificate & private key
+	CertFile string
+	KeyFile  string
+
+	// Config is in-memory tls.Config used as base configuration, it is cloned before use
+	Config *tls.Config
+
+	// MinVersion is minimum TLS version, e.g. tls.VersionTLS12. Default to tls.VersionTLS12
+	MinVersion uint16
+
+	// ClientCAFile is path to PEM encoded CA bundle used to verify client certificates
+	ClientCAFile string
+
+	// ClientAuth is client certificate policy. Default to tls.RequireAndVerifyClientCert when ClientCAFile is set
+	ClientAuth tls.ClientAuthType
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+var tlsClientAuthTypes = map[string]tls.ClientAuthType{
+	"none":               tls.NoClientCert,
+	"request":            tls.RequestClientCert,
+	"require":            tls.RequireAnyClientCert,
+	"verify_if_given":    tls.VerifyClientCertIfGiven,
+	"require_and_verify": tls.RequireAndVerifyClientCert,
+}
+
+// ParseTLSVersion parse TLS version string, e.g. "1.2", to tls.VersionTLS12
+func ParseTLSVersion(v string) (uint16, error) {
+	if ver, ok := tlsVersions[strings.TrimPrefix(strings.ToLower(v), "tls")]; ok {
+		return ver, nil
+	}
+
+	return 0, fmt.Errorf("unknown TLS version '%s'", v)
+}
+
+// ParseTLSClientAuth parse client auth policy string: none, request, require, verify_if_given or require_and_verify
+func ParseTLSClientAuth(v string) (tls.ClientAuthType, error) {
+	if a, ok := tlsClientAuthTypes[strings.ToLower(v)]; ok {
+		return a, nil
+	}
+
+	return tls.NoClientCert, fmt.Errorf("unknown TLS client auth '%s'", v)
+}
+
+// Enabled return true if TLS is configured
+func (c TLSCfg) Enabled() bool {
+	return c.Config != nil || c.CertFile != ""
+}
+
+func (c TLSCfg) validate() Errors {
+	var errs Errors
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		errs = append(errs, newConfigError("Cfg.TLS.CertFile", "and Cfg.TLS.KeyFile must be set together"))
+	}
+
+	if c.MinVersion != 0 && (c.MinVersion < tls.VersionTLS10 || c.MinVersion > tls.VersionTLS13) {
+		errs = append(errs, newConfigError("Cfg.TLS.MinVersion", fmt.Sprintf("is unknown TLS version 0x%04x", c.MinVersion)))
+	}
+
+	if !c.Enabled() && (c.ClientCAFile != "" || c.ClientAuth != tls.NoClientCert) {
+		errs = append(errs, newConfigError("Cfg.TLS.ClientCAFile", "require TLS to be enabled"))
+	}
+
+	if c.Config != nil && c.CertFile == "" && len(c.Config.Certificates) == 0 && c.Config.GetCertificate == nil {
+		errs = append(errs, newConfigError("Cfg.TLS.Config", "must have Certificates or GetCertificate when Cfg.TLS.CertFile is not set"))
+	}
+
+	if c.Config != nil && c.Config.ClientCAs != nil && c.ClientCAFile != "" {
+		errs = append(errs, newConfigError("Cfg.TLS.ClientCAFile", "can not be combined with Cfg.TLS.Config.ClientCAs"))
+	}
+
+	return errs
+}
+
+// Build return tls.Config for serving. Config is cloned, so it is never mutated
+func (c TLSCfg) Build() (*tls.Config, error) {
+	var tc *tls.Config
+	if c.Config != nil {
+		tc = c.Config.Clone()
+	} else {
+		tc = &tls.Config{}
+	}
+
+	if c.MinVersion != 0 {
+		tc.MinVersion = c.MinVersion
+	}
+
+	if tc.MinVersion == 0 {
+		tc.MinVersion = tls.VersionTLS12
+	}
+
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, NewCoreError(fmt.Sprintf("tls: load certificate error, %v", err))
+		}
+
+		tc.Certificates = append(tc.Certificates, cert)
+	}
+
+	if c.ClientCAFile != "" {
+		b, err := os.ReadFile(c.ClientCAFile)
+		if err != nil {
+			return nil, NewCoreError(fmt.Sprintf("tls: load client CA error, %v", err))
+		}
+
+		// Use new pool, so pool of Config is never mutated
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, NewCoreError(fmt.Sprintf("tls: no certificate found in client CA %s", c.ClientCAFile))
+		}
+
+		tc.ClientCAs = pool
+
+		if c.ClientAuth == tls.NoClientCert {
+			tc.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	if c.ClientAuth != tls.NoClientCert {
+		tc.ClientAuth = c.ClientAuth
+	}
+
+	return tc, nil
+}
+
+// ClientCertificate return verified client certificate of mutual TLS request, nil if the client is not verified
+func (c *HandlerCtx) ClientCertificate() *x509.Certificate {
+	if c.Request.TLS == nil || len(c.Request.TLS.VerifiedChains) == 0 || len(c.Request.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	return c.Request.TLS.VerifiedChains[0][0]
+}
+
+// ClientSubject return subject of verified client certificate, empty if the client is not verified
+func (c *HandlerCtx) ClientSubject() string {
+	cert := c.ClientCertificate()
+	if cert == nil {
+		return ""
+	}
+
+	return cert.Subject.String()
+}
@@ -0,0 +1,259 @@
+package noob
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	tlsCert tls.Certificate
+	pem     []byte
+}
+
+// newTestCert create certificate signed by parent, self-signed if parent is nil
+func newTestCert(t *testing.T, cn string, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key error: %v", err)
+	}
+
+	tpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn, Organization: []string{"noob"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  parent == nil,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	signer, signerKey := tpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("create certificate error: %v", err)
+	}
+
+	cert, _ := x509.ParseCertificate(der)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		tlsCert: tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key},
+		pem:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+func (c *testCert) pool() *x509.CertPool {
+	p := x509.NewCertPool()
+	p.AddCert(c.cert)
+
+	return p
+}
+
+func writeTestFile(t *testing.T, name string, b []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, b, 0600); err != nil {
+		t.Fatalf("write file error: %v", err)
+	}
+
+	return path
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	cases := map[string]uint16{
+		"1.0":    tls.VersionTLS10,
+		"1.2":    tls.VersionTLS12,
+		"TLS1.3": tls.VersionTLS13,
+	}
+
+	for in, expected := range cases {
+		v, err := ParseTLSVersion(in)
+		if err != nil || v != expected {
+			t.Errorf("ParseTLSVersion(%s) = %x, %v, expected %x", in, v, err, expected)
+		}
+	}
+
+	if _, err := ParseTLSVersion("2.0"); err == nil {
+		t.Error("expected error on unknown version")
+	}
+}
+
+func TestParseTLSClientAuth(t *testing.T) {
+	cases := map[string]tls.ClientAuthType{
+		"none":               tls.NoClientCert,
+		"request":            tls.RequestClientCert,
+		"require":            tls.RequireAnyClientCert,
+		"Verify_If_Given":    tls.VerifyClientCertIfGiven,
+		"require_and_verify": tls.RequireAndVerifyClientCert,
+	}
+
+	for in, expected := range cases {
+		v, err := ParseTLSClientAuth(in)
+		if err != nil || v != expected {
+			t.Errorf("ParseTLSClientAuth(%s) = %v, %v, expected %v", in, v, err, expected)
+		}
+	}
+
+	if _, err := ParseTLSClientAuth("always"); err == nil {
+		t.Error("expected error on unknown client auth")
+	}
+}
+
+func TestTLSCfgValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  TLSCfg
+		errs int
+	}{
+		{"disabled", TLSCfg{}, 0},
+		{"cert without key", TLSCfg{CertFile: "cert.pem"}, 1},
+		{"config without certificate", TLSCfg{Config: &tls.Config{}}, 1},
+		{"client CA without TLS", TLSCfg{ClientCAFile: "ca.pem"}, 1},
+		{"client CA file with config pool", TLSCfg{Config: &tls.Config{GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return nil, nil }, ClientCAs: x509.NewCertPool()}, ClientCAFile: "ca.pem"}, 1},
+	}
+
+	for _, c := range cases {
+		if errs := c.cfg.validate(); len(errs) != c.errs {
+			t.Errorf("%s: expected %d errors, got %v", c.name, c.errs, errs)
+		}
+	}
+}
+
+func TestTLSCfgBuildNotMutateConfig(t *testing.T) {
+	ca := newTestCert(t, "ca", nil)
+	server := newTestCert(t, "server", ca)
+	caFile := writeTestFile(t, "ca.pem", ca.pem)
+
+	base := &tls.Config{Certificates: []tls.Certificate{server.tlsCert}}
+	cfg := TLSCfg{Config: base, ClientCAFile: caFile}
+
+	tc, err := cfg.Build()
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	if base.ClientCAs != nil || base.ClientAuth != tls.NoClientCert || base.MinVersion != 0 {
+		t.Fatal("expected base config not to be mutated")
+	}
+
+	if tc.ClientCAs == nil || tc.ClientAuth != tls.RequireAndVerifyClientCert || tc.MinVersion != tls.VersionTLS12 {
+		t.Fatalf("unexpected built config: %+v", tc)
+	}
+}
+
+func TestMutualTLSClientSubject(t *testing.T) {
+	ca := newTestCert(t, "ca", nil)
+	server := newTestCert(t, "server", ca)
+	client := newTestCert(t, "svc-billing", ca)
+
+	app := NewWithOptions()
+	app.GET("/whoami", func(c *HandlerCtx) (Response, error) {
+		if c.ClientCertificate() == nil {
+			return nil, DefaultForbiddenErrorResponse
+		}
+
+		return NewResponseSuccess(ResponseBody{Message: c.ClientSubject()}), nil
+	})
+	if err := app.Provider.preRun(); err != nil {
+		t.Fatalf("boot error: %v", err)
+	}
+
+	tc, err := TLSCfg{
+		Config:       &tls.Config{Certificates: []tls.Certificate{server.tlsCert}},
+		ClientCAFile: writeTestFile(t, "ca.pem", ca.pem),
+	}.Build()
+	if err != nil {
+		t.Fatalf("build error: %v", err)
+	}
+
+	srv := httptest.NewUnstartedServer(app.Provider.Engine)
+	srv.TLS = tc
+	srv.StartTLS()
+	defer srv.Close()
+
+	authenticated := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      ca.pool(),
+		Certificates: []tls.Certificate{client.tlsCert},
+	}}}
+
+	res, err := authenticated.Get(srv.URL + "/whoami")
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	defer res.Body.Close()
+
+	var body ResponseBody
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+
+	if body.Message != client.cert.Subject.String() {
+		t.Fatalf("expected subject %s, got %s", client.cert.Subject, body.Message)
+	}
+
+	anonymous := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: ca.pool()}}}
+	if res, err := anonymous.Get(srv.URL + "/whoami"); err == nil {
+		res.Body.Close()
+		t.Fatal("expected client without certificate to be rejected")
+	}
+}
+
+func TestStartServeTLS(t *testing.T) {
+	ca := newTestCert(t, "ca", nil)
+	server := newTestCert(t, "server", ca)
+
+	certFile := writeTestFile(t, "server.pem", server.pem)
+	keyDer, _ := x509.MarshalECPrivateKey(server.key)
+	keyFile := writeTestFile(t, "server-key.pem", pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+
+	app := newTestApp(t, func(cfg *Cfg) {
+		cfg.TLS = TLSCfg{CertFile: certFile, KeyFile: keyFile, MinVersion: tls.VersionTLS13}
+	})
+	app.start(t)
+	defer app.stop(t)
+
+	cl := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: ca.pool()}}}
+	res, err := cl.Get("https://" + app.addr + "/")
+	if err != nil {
+		t.Fatalf("request error: %v", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusOK || res.TLS == nil || res.TLS.Version != tls.VersionTLS13 {
+		t.Fatalf("expected 200 over TLS 1.3, got %d %+v", res.StatusCode, res.TLS)
+	}
+
+	// Go TLS server answer plain HTTP request with 400
+	if res, err := http.Get(app.url("/")); err == nil {
+		res.Body.Close()
+		if res.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected plain HTTP request to be rejected, got %d", res.StatusCode)
+		}
+	}
+}
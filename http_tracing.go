@@ -0,0 +1,140 @@
+package noob
+
+import (
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+// tracerName is instrumentation name of spans created by the package
+const tracerName = "github.com/alfarih31/nb-go-http"
+
+const extKeyTracing = "_tracing"
+
+// TracingCfg is configuration of tracing middleware
+type TracingCfg struct {
+	// TracerProvider create the tracer, default to otel.GetTracerProvider()
+	TracerProvider trace.TracerProvider
+
+	// Propagator extract trace context from request headers & inject it to response headers, default to W3C trace context
+	Propagator propagation.TextMapPropagator
+
+	// HandlerSpans create child span for every Handler of the handler chains named by Handler.String(), including middlewares
+	// & postwares, so slow handlers can be found
+	HandlerSpans bool
+
+	// SkipPaths is request paths not traced, e.g. "/" for health checks
+	SkipPaths []string
+}
+
+type tracing struct {
+	tracer       trace.Tracer
+	propagator   propagation.TextMapPropagator
+	handlerSpans bool
+}
+
+// HandleTracing return middleware creating server span of every request. Trace context of the request is extracted from the
+// headers & injected to the response headers. Errors passed to SendError are recorded on the span
+func HandleTracing(cfg TracingCfg) HandlerFunc {
+	if cfg.TracerProvider == nil {
+		cfg.TracerProvider = otel.GetTracerProvider()
+	}
+
+	if cfg.Propagator == nil {
+		cfg.Propagator = propagation.TraceContext{}
+	}
+
+	t := &tracing{
+		tracer:       cfg.TracerProvider.Tracer(tracerName),
+		propagator:   cfg.Propagator,
+		handlerSpans: cfg.HandlerSpans,
+	}
+
+	skip := map[string]bool{}
+	for _, p := range cfg.SkipPaths {
+		skip[p] = true
+	}
+
+	return func(c *HandlerCtx) (res Response, err error) {
+		if skip[c.Request.URL.Path] {
+			return c.Next()
+		}
+
+		route := c.FullPath()
+		name := c.Request.Method
+		if route != "" {
+			name += " " + route
+		}
+
+		ctx := t.propagator.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := t.tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest("", route, c.Request)...),
+		)
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Set(extKeyTracing, t)
+		t.propagator.Inject(ctx, propagation.HeaderCarrier(c.Writer.Header()))
+
+		defer func() {
+			if r := recover(); r != nil {
+				span.RecordError(fmt.Errorf("%v", r))
+				span.SetStatus(codes.Error, "panic")
+				span.End()
+
+				panic(r)
+			}
+
+			// Error of the middlewares is sent after they return, so record it here
+			if err != nil {
+				span.RecordError(err)
+			}
+
+			status := c.responseStatus(res, err)
+			span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+			span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, trace.SpanKindServer))
+
+			if id := c.RequestID(); id != "" {
+				span.SetAttributes(attribute.String("http.request_id", id))
+			}
+
+			span.End()
+		}()
+
+		return c.Next()
+	}
+}
+
+// handle run h in child span of the current span named by h.String()
+func (t *tracing) handle(c *HandlerCtx, h Handler) (res Response, err error) {
+	parent := trace.SpanFromContext(c.Request.Context())
+
+	ctx, span := t.tracer.Start(c.Request.Context(), h.String())
+	c.Request = c.Request.WithContext(ctx)
+
+	defer func() {
+		// Restore the parent span, keep other values set by the handler on the context
+		c.Request = c.Request.WithContext(trace.ContextWithSpan(c.Request.Context(), parent))
+		span.End()
+	}()
+
+	res, err = h.fn(c)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return res, err
+}
+
+// InjectTraceContext inject trace context of the request to header, e.g. to propagate it to outgoing requests. It is no-op if
+// HandleTracing is not used
+func (c *HandlerCtx) InjectTraceContext(header http.Header) {
+	if t, ok := c.Keys[extKeyTracing].(*tracing); ok {
+		t.propagator.Inject(c.Request.Context(), propagation.HeaderCarrier(header))
+	}
+}
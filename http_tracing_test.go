@@ -0,0 +1,202 @@
+package noob
+
+import (
+	"errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func newTracingTestApp(t *testing.T, cfg TracingCfg, opts ...Option) (http.Handler, *tracetest.InMemoryExporter) {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	cfg.TracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	app := NewWithOptions(append([]Option{WithTracing(cfg)}, opts...)...)
+	app.GET("/users/:id", routesHandler)
+	app.GET("/fail", func(c *HandlerCtx) (Response, error) {
+		return nil, errors.New("boom")
+	})
+	app.GET("/outgoing", func(c *HandlerCtx) (Response, error) {
+		h := http.Header{}
+		c.InjectTraceContext(h)
+
+		return NewResponseSuccess(ResponseBody{Data: h.Get("traceparent")}), nil
+	})
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	return h, exporter
+}
+
+func spanAttribute(s tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, a := range s.Attributes {
+		if a.Key == key {
+			return a.Value
+		}
+	}
+
+	return attribute.Value{}
+}
+
+func serverSpan(t *testing.T, exporter *tracetest.InMemoryExporter) tracetest.SpanStub {
+	t.Helper()
+
+	for _, s := range exporter.GetSpans() {
+		if s.SpanKind == trace.SpanKindServer {
+			return s
+		}
+	}
+
+	t.Fatalf("expected server span, got %v", exporter.GetSpans())
+
+	return tracetest.SpanStub{}
+}
+
+func TestTracingServerSpan(t *testing.T) {
+	h, exporter := newTracingTestApp(t, TracingCfg{})
+
+	rec := serveMethod(t, h, http.MethodGet, "/users/42", "traceparent", testTraceparent, HeaderRequestID, "req-1")
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected only server span, got %d", len(spans))
+	}
+
+	s := serverSpan(t, exporter)
+	if s.Name != "GET /users/:id" {
+		t.Fatalf("expected span name of route pattern, got %s", s.Name)
+	}
+
+	// Trace is continued from the incoming traceparent
+	if s.SpanContext.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || !s.Parent.IsRemote() ||
+		s.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Fatalf("expected remote parent of traceparent, got %v", s.Parent)
+	}
+
+	if got := spanAttribute(s, "http.status_code").AsInt64(); got != 200 {
+		t.Fatalf("expected status code 200, got %d", got)
+	}
+
+	if got := spanAttribute(s, "http.route").AsString(); got != "/users/:id" {
+		t.Fatalf("expected route attribute, got %s", got)
+	}
+
+	if got := spanAttribute(s, "http.request_id").AsString(); got != "req-1" {
+		t.Fatalf("expected request ID attribute, got %s", got)
+	}
+
+	// Trace context is injected to the response
+	tp := rec.Header().Get("traceparent")
+	if !strings.HasPrefix(tp, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+s.SpanContext.SpanID().String()) {
+		t.Fatalf("expected traceparent of the server span, got %s", tp)
+	}
+}
+
+func TestTracingInjectTraceContext(t *testing.T) {
+	h, exporter := newTracingTestApp(t, TracingCfg{})
+
+	rec := serveMethod(t, h, http.MethodGet, "/outgoing")
+
+	s := serverSpan(t, exporter)
+	if !strings.Contains(rec.Body.String(), s.SpanContext.TraceID().String()) {
+		t.Fatalf("expected traceparent of the request trace, got %s", rec.Body.String())
+	}
+}
+
+func TestTracingHandlerSpans(t *testing.T) {
+	h, exporter := newTracingTestApp(t, TracingCfg{HandlerSpans: true})
+
+	serveMethod(t, h, http.MethodGet, "/users/42")
+
+	s := serverSpan(t, exporter)
+
+	byName := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		byName[span.Name] = span
+	}
+
+	requestID, ok := byName[NewHandler(HandleRequestID).String()]
+	if !ok || requestID.Parent.SpanID() != s.SpanContext.SpanID() {
+		t.Fatalf("expected span of middleware child of server span, got %v", byName)
+	}
+
+	handler, ok := byName[NewHandler(routesHandler).String()]
+	if !ok || handler.SpanContext.TraceID() != s.SpanContext.TraceID() {
+		t.Fatalf("expected span of route handler in the trace, got %v", byName)
+	}
+
+	// Handlers of the route run inside the last middleware
+	if timeout := byName[NewHandler(HandleTimeout).String()]; handler.Parent.SpanID() != timeout.SpanContext.SpanID() {
+		t.Fatalf("expected span of route handler child of HandleTimeout span, got parent %v", handler.Parent.SpanID())
+	}
+}
+
+func TestTracingRecordError(t *testing.T) {
+	h, exporter := newTracingTestApp(t, TracingCfg{})
+
+	serveMethod(t, h, http.MethodGet, "/fail")
+
+	s := serverSpan(t, exporter)
+	if s.Status.Code != codes.Error {
+		t.Fatalf("expected error status, got %v", s.Status)
+	}
+
+	if len(s.Events) != 1 || s.Events[0].Name != "exception" {
+		t.Fatalf("expected exception event, got %v", s.Events)
+	}
+
+	if got := spanAttribute(s, "http.status_code").AsInt64(); got != 500 {
+		t.Fatalf("expected status code 500, got %d", got)
+	}
+}
+
+func TestTracingMiddlewareError(t *testing.T) {
+	h, exporter := newTracingTestApp(t, TracingCfg{},
+		WithThrottlingCfg(ThrottlingCfg{Enable: true, MaxEventPerSec: 1, MaxBurstSize: 1}))
+
+	serveMethod(t, h, http.MethodGet, "/missing")
+	serveMethod(t, h, http.MethodGet, "/missing")
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+
+	for i, want := range []int64{404, 429} {
+		s := spans[i]
+		if s.Name != "GET" {
+			t.Fatalf("expected span name of unmatched request, got %s", s.Name)
+		}
+
+		if got := spanAttribute(s, "http.status_code").AsInt64(); got != want {
+			t.Fatalf("expected status code %d, got %d", want, got)
+		}
+
+		// 4xx is not error of server span, but the error is recorded
+		if s.Status.Code == codes.Error || len(s.Events) != 1 {
+			t.Fatalf("expected unset status with exception event, got %v & %v", s.Status, s.Events)
+		}
+	}
+}
+
+func TestTracingSkipPaths(t *testing.T) {
+	h, exporter := newTracingTestApp(t, TracingCfg{SkipPaths: []string{"/"}})
+
+	serveMethod(t, h, http.MethodGet, "/")
+
+	if len(exporter.GetSpans()) != 0 {
+		t.Fatalf("expected no span, got %v", exporter.GetSpans())
+	}
+}
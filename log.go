@@ -1,13 +1,181 @@
 package noob
 
-import _logger "github.com/alfarih31/nb-go-logger"
+import (
+	_logger "github.com/alfarih31/nb-go-logger"
+	"github.com/sirupsen/logrus"
+	"sync/atomic"
+)
 
-var log = _logger.New("core")
+// syncLogger is _logger.Logger which underlying logger can be replaced safely while it is used by other goroutines
+type syncLogger struct {
+	v atomic.Value
+}
+
+// loggerHolder keep concrete type stored in atomic.Value consistent
+type loggerHolder struct {
+	_logger.Logger
+}
+
+func newSyncLogger(l _logger.Logger) *syncLogger {
+	s := new(syncLogger)
+	s.set(l)
+
+	return s
+}
+
+func (s *syncLogger) set(l _logger.Logger) {
+	s.v.Store(loggerHolder{l})
+}
+
+func (s *syncLogger) get() _logger.Logger {
+	return s.v.Load().(loggerHolder).Logger
+}
 
-var logR = log.NewChild("response")
+func (s *syncLogger) Info(m interface{}, opts ...interface{}) _logger.Logger {
+	return s.get().Info(m, opts...)
+}
+
+func (s *syncLogger) Infof(f string, opts ...interface{}) _logger.Logger {
+	return s.get().Infof(f, opts...)
+}
+
+func (s *syncLogger) Warn(m interface{}, opts ...interface{}) _logger.Logger {
+	return s.get().Warn(m, opts...)
+}
+
+func (s *syncLogger) Warnf(f string, opts ...interface{}) _logger.Logger {
+	return s.get().Warnf(f, opts...)
+}
+
+func (s *syncLogger) Debug(m interface{}, opts ...interface{}) _logger.Logger {
+	return s.get().Debug(m, opts...)
+}
+
+func (s *syncLogger) Debugf(f string, opts ...interface{}) _logger.Logger {
+	return s.get().Debugf(f, opts...)
+}
+
+func (s *syncLogger) Error(m interface{}, opts ...interface{}) _logger.Logger {
+	return s.get().Error(m, opts...)
+}
+
+func (s *syncLogger) Errorf(f string, opts ...interface{}) _logger.Logger {
+	return s.get().Errorf(f, opts...)
+}
+
+func (s *syncLogger) Fatal(m interface{}, opts ...interface{}) {
+	s.get().Fatal(m, opts...)
+}
+
+func (s *syncLogger) Fatalf(f string, opts ...interface{}) {
+	s.get().Fatalf(f, opts...)
+}
+
+func (s *syncLogger) NewChild(cname string) _logger.Logger {
+	return s.get().NewChild(cname)
+}
+
+func (s *syncLogger) SetLevel(level string) _logger.Logger {
+	return s.get().SetLevel(level)
+}
+
+func (s *syncLogger) AddHook(hook logrus.Hook) _logger.Logger {
+	return s.get().AddHook(hook)
+}
+
+func (s *syncLogger) GetLevel() _logger.LogLevel {
+	return s.get().GetLevel()
+}
 
+var log = newSyncLogger(_logger.New("core"))
+
+var logR = newSyncLogger(log.NewChild("response"))
+
+// restartLogger re-create the loggers to re-read env. It is safe to be called while other applications are serving
 func restartLogger() {
-	log = _logger.New("core")
+	l := _logger.New("core")
+
+	log.set(l)
+
+	logR.set(l.NewChild("response"))
+}
+
+// fieldsLogger is _logger.Logger adding fields to every log line
+type fieldsLogger struct {
+	_logger.Logger
+	fields map[string]interface{}
+}
+
+func withFields(l _logger.Logger, fields map[string]interface{}) _logger.Logger {
+	return fieldsLogger{Logger: l, fields: fields}
+}
+
+func (f fieldsLogger) opts(opts []interface{}) []interface{} {
+	return append([]interface{}{f.fields}, opts...)
+}
+
+func (f fieldsLogger) Info(m interface{}, opts ...interface{}) _logger.Logger {
+	f.Logger.Info(m, f.opts(opts)...)
+	return f
+}
+
+func (f fieldsLogger) Infof(format string, opts ...interface{}) _logger.Logger {
+	f.Logger.Infof(format, f.opts(opts)...)
+	return f
+}
+
+func (f fieldsLogger) Warn(m interface{}, opts ...interface{}) _logger.Logger {
+	f.Logger.Warn(m, f.opts(opts)...)
+	return f
+}
+
+func (f fieldsLogger) Warnf(format string, opts ...interface{}) _logger.Logger {
+	f.Logger.Warnf(format, f.opts(opts)...)
+	return f
+}
+
+func (f fieldsLogger) Debug(m interface{}, opts ...interface{}) _logger.Logger {
+	f.Logger.Debug(m, f.opts(opts)...)
+	return f
+}
+
+func (f fieldsLogger) Debugf(format string, opts ...interface{}) _logger.Logger {
+	f.Logger.Debugf(format, f.opts(opts)...)
+	return f
+}
+
+func (f fieldsLogger) Error(m interface{}, opts ...interface{}) _logger.Logger {
+	f.Logger.Error(m, f.opts(opts)...)
+	return f
+}
+
+func (f fieldsLogger) Errorf(format string, opts ...interface{}) _logger.Logger {
+	f.Logger.Errorf(format, f.opts(opts)...)
+	return f
+}
+
+func (f fieldsLogger) Fatal(m interface{}, opts ...interface{}) {
+	f.Logger.Fatal(m, f.opts(opts)...)
+}
+
+func (f fieldsLogger) Fatalf(format string, opts ...interface{}) {
+	f.Logger.Fatalf(format, f.opts(opts)...)
+}
+
+func (f fieldsLogger) NewChild(cname string) _logger.Logger {
+	return withFields(f.Logger.NewChild(cname), f.fields)
+}
+
+// Logger return logger of the request, adding request ID to every log line
+func (c *HandlerCtx) Logger() _logger.Logger {
+	return c.logger(log)
+}
+
+// logger return l adding request ID to every log line
+func (c *HandlerCtx) logger(l _logger.Logger) _logger.Logger {
+	if id := c.RequestID(); id != "" {
+		return withFields(l, map[string]interface{}{"requestId": id})
+	}
 
-	logR = log.NewChild("response")
+	return l
 }
@@ -0,0 +1,347 @@
+// Package noobtest provide in-process client to test noob applications without binding a port.
+//
+//	noobtest.New(t, app).GET("/sample/first-inner").Expect(200).JSONPath("message", "G1 First")
+package noobtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/alfarih31/nb-go-http"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// App is application which can be booted to http.Handler, e.g. *noob.Ctx or *noob.HTTPProviderCtx
+type App interface {
+	Handler() (http.Handler, error)
+}
+
+// Client serve requests in-process with the booted handler of the application
+type Client struct {
+	t       testing.TB
+	handler http.Handler
+	header  http.Header
+}
+
+// New boot app & return Client for it. The test is failed immediately if the app can not be booted
+func New(t testing.TB, app App) *Client {
+	t.Helper()
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("noobtest: boot error: %v", err)
+	}
+
+	return &Client{
+		t:       t,
+		handler: h,
+		header:  http.Header{},
+	}
+}
+
+// WithHeader set header sent on every request of the Client
+func (c *Client) WithHeader(key string, value string) *Client {
+	c.header.Set(key, value)
+
+	return c
+}
+
+// Request create request of method to path. Path may contain query string
+func (c *Client) Request(method string, path string) *Request {
+	return &Request{
+		c:      c,
+		method: method,
+		path:   path,
+		header: c.header.Clone(),
+		query:  url.Values{},
+	}
+}
+
+func (c *Client) GET(path string) *Request {
+	return c.Request(http.MethodGet, path)
+}
+
+func (c *Client) POST(path string) *Request {
+	return c.Request(http.MethodPost, path)
+}
+
+func (c *Client) PUT(path string) *Request {
+	return c.Request(http.MethodPut, path)
+}
+
+func (c *Client) PATCH(path string) *Request {
+	return c.Request(http.MethodPatch, path)
+}
+
+func (c *Client) DELETE(path string) *Request {
+	return c.Request(http.MethodDelete, path)
+}
+
+func (c *Client) HEAD(path string) *Request {
+	return c.Request(http.MethodHead, path)
+}
+
+func (c *Client) OPTIONS(path string) *Request {
+	return c.Request(http.MethodOptions, path)
+}
+
+// Request is pending request, it is served on Do or Expect
+type Request struct {
+	c      *Client
+	method string
+	path   string
+	header http.Header
+	query  url.Values
+	body   []byte
+}
+
+// WithHeader set header of the request
+func (r *Request) WithHeader(key string, value string) *Request {
+	r.header.Set(key, value)
+
+	return r
+}
+
+// WithQuery add query parameter to the request
+func (r *Request) WithQuery(key string, value string) *Request {
+	r.query.Add(key, value)
+
+	return r
+}
+
+// WithBody set body of the request with contentType
+func (r *Request) WithBody(body io.Reader, contentType string) *Request {
+	r.c.t.Helper()
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		r.c.t.Fatalf("noobtest: read body error: %v", err)
+	}
+
+	r.body = b
+	r.header.Set("Content-Type", contentType)
+
+	return r
+}
+
+// WithJSON set JSON encoded v as body of the request
+func (r *Request) WithJSON(v interface{}) *Request {
+	r.c.t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		r.c.t.Fatalf("noobtest: encode JSON body error: %v", err)
+	}
+
+	return r.WithBody(bytes.NewReader(b), "application/json")
+}
+
+// Do serve the request & return its Response
+func (r *Request) Do() *Response {
+	target := r.path
+	if len(r.query) > 0 {
+		sep := "?"
+		if strings.Contains(target, "?") {
+			sep = "&"
+		}
+		target += sep + r.query.Encode()
+	}
+
+	req := httptest.NewRequest(r.method, target, bytes.NewReader(r.body))
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+
+	rec := httptest.NewRecorder()
+	r.c.handler.ServeHTTP(rec, req)
+
+	return &Response{
+		t:        r.c.t,
+		Recorder: rec,
+	}
+}
+
+// Expect serve the request & assert status code of the Response
+func (r *Request) Expect(code int) *Response {
+	r.c.t.Helper()
+
+	return r.Do().Expect(code)
+}
+
+// Response is recorded response of a Request. Assertion failures are reported with testing.TB.Errorf, so assertions can be chained
+type Response struct {
+	t        testing.TB
+	Recorder *httptest.ResponseRecorder
+
+	decoded bool
+	value   interface{}
+	body    noob.ResponseBody
+	err     error
+}
+
+// Code return status code of the Response
+func (r *Response) Code() int {
+	return r.Recorder.Code
+}
+
+// Header return header of the Response
+func (r *Response) Header() http.Header {
+	return r.Recorder.Header()
+}
+
+// Bytes return raw body of the Response
+func (r *Response) Bytes() []byte {
+	return r.Recorder.Body.Bytes()
+}
+
+func (r *Response) decode() error {
+	if !r.decoded {
+		r.decoded = true
+		if r.err = json.Unmarshal(r.Bytes(), &r.value); r.err == nil {
+			r.err = json.Unmarshal(r.Bytes(), &r.body)
+		}
+	}
+
+	return r.err
+}
+
+// Body return decoded noob.ResponseBody of the Response
+func (r *Response) Body() noob.ResponseBody {
+	r.t.Helper()
+
+	if err := r.decode(); err != nil {
+		r.t.Errorf("noobtest: decode body error: %v, body: %s", err, r.Bytes())
+	}
+
+	return r.body
+}
+
+// Errors return decoded `_error` field of the Response
+func (r *Response) Errors() interface{} {
+	r.t.Helper()
+
+	return r.Body().Errors
+}
+
+// Decode decode JSON body of the Response to v
+func (r *Response) Decode(v interface{}) *Response {
+	r.t.Helper()
+
+	if err := json.Unmarshal(r.Bytes(), v); err != nil {
+		r.t.Errorf("noobtest: decode body error: %v, body: %s", err, r.Bytes())
+	}
+
+	return r
+}
+
+// Expect assert status code of the Response
+func (r *Response) Expect(code int) *Response {
+	r.t.Helper()
+
+	if r.Code() != code {
+		r.t.Errorf("noobtest: expected status %d, got %d, body: %s", code, r.Code(), r.Bytes())
+	}
+
+	return r
+}
+
+// ExpectHeader assert value of header key of the Response
+func (r *Response) ExpectHeader(key string, value string) *Response {
+	r.t.Helper()
+
+	if got := r.Header().Get(key); got != value {
+		r.t.Errorf("noobtest: expected header %s = %q, got %q", key, value, got)
+	}
+
+	return r
+}
+
+// Lookup return value at path of JSON body. Path is dot separated keys & array indexes, e.g. "data.items.0.name"
+func (r *Response) Lookup(path string) (interface{}, bool) {
+	if r.decode() != nil {
+		return nil, false
+	}
+
+	return lookup(r.value, path)
+}
+
+// JSONPath assert value at path of JSON body equal to want, compared by their JSON representation
+func (r *Response) JSONPath(path string, want interface{}) *Response {
+	r.t.Helper()
+
+	if err := r.decode(); err != nil {
+		r.t.Errorf("noobtest: decode body error: %v, body: %s", err, r.Bytes())
+		return r
+	}
+
+	got, ok := lookup(r.value, path)
+	if !ok {
+		r.t.Errorf("noobtest: path %q not found, body: %s", path, r.Bytes())
+		return r
+	}
+
+	if !jsonEqual(got, want) {
+		r.t.Errorf("noobtest: expected %q = %v, got %v", path, want, got)
+	}
+
+	return r
+}
+
+// ErrorPath is JSONPath of the `_error` field, empty path assert the whole field
+func (r *Response) ErrorPath(path string, want interface{}) *Response {
+	r.t.Helper()
+
+	if path == "" {
+		return r.JSONPath("_error", want)
+	}
+
+	return r.JSONPath("_error."+path, want)
+}
+
+func lookup(v interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return v, true
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			child, exist := node[key]
+			if !exist {
+				return nil, false
+			}
+			v = child
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			v = node[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// jsonEqual compare got, a decoded JSON value, with want after round-trip of want to JSON
+func jsonEqual(got interface{}, want interface{}) bool {
+	b, err := json.Marshal(want)
+	if err != nil {
+		return false
+	}
+
+	var w interface{}
+	if err := json.Unmarshal(b, &w); err != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(got, w)
+}
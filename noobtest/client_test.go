@@ -0,0 +1,121 @@
+package noobtest
+
+import (
+	"errors"
+	"fmt"
+	"github.com/alfarih31/nb-go-http"
+	keyvalue "github.com/alfarih31/nb-go-keyvalue"
+	"net/http"
+	"testing"
+)
+
+// recordTB record failures instead of failing the test
+type recordTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordTB) Helper() {}
+
+func (r *recordTB) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func newProvider() *noob.HTTPProviderCtx {
+	p := noob.HTTP()
+
+	g1 := p.Router("/sample")
+	g1.GET("/first-inner", func(c *noob.HandlerCtx) (noob.Response, error) {
+		return noob.NewResponseSuccess(noob.ResponseBody{
+			Message: "G1 First",
+			Data:    map[string]interface{}{"items": []int{1, 2, 3}},
+		}), nil
+	})
+
+	g1.POST("/echo", func(c *noob.HandlerCtx) (noob.Response, error) {
+		var body map[string]interface{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			return nil, err
+		}
+
+		return noob.NewResponseSuccess(noob.ResponseBody{
+			Message: c.GetHeader("X-Test") + " " + c.Query("q"),
+			Data:    body,
+		}), nil
+	})
+
+	g1.GET("/error", func(c *noob.HandlerCtx) (noob.Response, error) {
+		return nil, errors.New("this is an error")
+	})
+
+	return p
+}
+
+func TestClientGET(t *testing.T) {
+	New(t, newProvider()).GET("/sample/first-inner").
+		Expect(http.StatusOK).
+		JSONPath("message", "G1 First").
+		JSONPath("data.items.1", 2).
+		JSONPath("data.items", []int{1, 2, 3})
+}
+
+func TestClientPOST(t *testing.T) {
+	res := New(t, newProvider()).WithHeader("X-Test", "hello").
+		POST("/sample/echo").
+		WithQuery("q", "world").
+		WithJSON(map[string]interface{}{"name": "noob"}).
+		Expect(http.StatusOK).
+		JSONPath("message", "hello world").
+		JSONPath("data.name", "noob")
+
+	if res.Body().Message != "hello world" {
+		t.Fatalf("expected decoded message, got %+v", res.Body())
+	}
+}
+
+func TestClientError(t *testing.T) {
+	// _error is the error message when not debugging
+	t.Setenv("DEBUG", "false")
+
+	res := New(t, newProvider()).GET("/sample/error").
+		Expect(http.StatusInternalServerError).
+		ErrorPath("", "this is an error")
+
+	if res.Errors() != "this is an error" {
+		t.Fatalf("expected _error, got %v", res.Errors())
+	}
+}
+
+func TestClientReportFailures(t *testing.T) {
+	tb := &recordTB{TB: t}
+
+	New(tb, newProvider()).GET("/sample/first-inner").
+		Expect(http.StatusCreated).
+		JSONPath("message", "G1 Second").
+		JSONPath("data.missing", 1).
+		ExpectHeader("Content-Type", "text/plain")
+
+	if len(tb.failures) != 4 {
+		t.Fatalf("expected 4 failures, got %d: %v", len(tb.failures), tb.failures)
+	}
+}
+
+func TestClientBootOnce(t *testing.T) {
+	p := newProvider()
+
+	// Booting twice must not register routes twice
+	New(t, p).GET("/sample/first-inner").Expect(http.StatusOK)
+	New(t, p).GET("/sample/first-inner").Expect(http.StatusOK)
+}
+
+func TestClientApp(t *testing.T) {
+	app := noob.NewWithOptions(noob.WithMeta(keyvalue.KeyValue{"app_name": "test"}))
+	app.GET("/ping", func(c *noob.HandlerCtx) (noob.Response, error) {
+		return noob.DefaultSuccessResponse, nil
+	})
+
+	cl := New(t, app)
+	cl.GET("/").Expect(http.StatusOK).JSONPath("data.app_name", "test")
+	cl.GET("/ping").Expect(http.StatusOK)
+	cl.GET("/missing").Expect(http.StatusNotFound).JSONPath("message", "not found")
+}
@@ -0,0 +1,98 @@
+package noob
+
+import (
+	"github.com/alfarih31/nb-go-http/utils"
+	"time"
+)
+
+// ParamParser parse path parameters of the route, e.g. id of /users/:id, with the same options as QueryParser
+type ParamParser HandlerCtx
+
+func (p ParamParser) parser() valueParser {
+	return valueParser{name: "path", source: p.paramValues}
+}
+
+func (p ParamParser) GetString(key string, opt ...QueryParserOption) (*string, error) {
+	return p.parser().getString(key, opt)
+}
+
+func (p ParamParser) GetBool(key string, opt ...QueryParserOption) (*bool, error) {
+	return p.parser().getBool(key, opt)
+}
+
+func (p ParamParser) GetInt(key string, opt ...QueryParserOption) (*int, error) {
+	return p.parser().getInt(key, opt)
+}
+
+// GetInt32 return value of key as int32, value out of int32 range is invalid
+func (p ParamParser) GetInt32(key string, opt ...QueryParserOption) (*int32, error) {
+	return p.parser().getInt32(key, opt)
+}
+
+func (p ParamParser) GetInt64(key string, opt ...QueryParserOption) (*int64, error) {
+	return p.parser().getInt64(key, opt)
+}
+
+func (p ParamParser) GetFloat32(key string, opt ...QueryParserOption) (*float32, error) {
+	return p.parser().getFloat32(key, opt)
+}
+
+func (p ParamParser) GetFloat64(key string, opt ...QueryParserOption) (*float64, error) {
+	return p.parser().getFloat64(key, opt)
+}
+
+// GetUint return value of key as uint, negative value is invalid
+func (p ParamParser) GetUint(key string, opt ...QueryParserOption) (*uint, error) {
+	return p.parser().getUint(key, opt)
+}
+
+func (p ParamParser) GetUint32(key string, opt ...QueryParserOption) (*uint32, error) {
+	return p.parser().getUint32(key, opt)
+}
+
+func (p ParamParser) GetUint64(key string, opt ...QueryParserOption) (*uint64, error) {
+	return p.parser().getUint64(key, opt)
+}
+
+// GetDuration return value of key parsed by time.ParseDuration, e.g. 1m30s
+func (p ParamParser) GetDuration(key string, opt ...QueryParserOption) (*time.Duration, error) {
+	return p.parser().getDuration(key, opt)
+}
+
+// GetTime return value of key parsed as RFC3339 timestamp. Default must be *utils.Datetime
+func (p ParamParser) GetTime(key string, opt ...QueryParserOption) (*utils.Datetime, error) {
+	return p.parser().getTime(key, opt)
+}
+
+// GetStrings return comma separated values of key, e.g. /users/:ids of /users/1,2
+func (p ParamParser) GetStrings(key string, opt ...QueryParserOption) ([]string, error) {
+	return p.parser().getStrings(key, opt)
+}
+
+// GetInts return comma separated values of key as []int
+func (p ParamParser) GetInts(key string, opt ...QueryParserOption) ([]int, error) {
+	return p.parser().getInts(key, opt)
+}
+
+func (p ParamParser) GetInt64s(key string, opt ...QueryParserOption) ([]int64, error) {
+	return p.parser().getInt64s(key, opt)
+}
+
+func (p ParamParser) GetFloat64s(key string, opt ...QueryParserOption) ([]float64, error) {
+	return p.parser().getFloat64s(key, opt)
+}
+
+// Bind bind path parameters to fields of dst tagged by `path:"name"` with the same rules as QueryParser.Bind
+func (p ParamParser) Bind(dst interface{}) error {
+	return binder{sources: []binderSource{{tag: "path", source: p.paramValues}}}.bind(dst)
+}
+
+// paramValues return value of path parameter key, empty value is missing
+func (p ParamParser) paramValues(key string) ([]string, bool) {
+	v := p.Param(key)
+	if v == "" {
+		return nil, false
+	}
+
+	return []string{v}, true
+}
@@ -0,0 +1,351 @@
+package noob
+
+import (
+	"errors"
+	"fmt"
+	"github.com/alfarih31/nb-go-http/utils"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	datetimeType = reflect.TypeOf(utils.Datetime{})
+
+	errUnsupportedType = errors.New("unsupported type")
+)
+
+// bindSource return values of key from a part of the request, ok is false when the key is missing or empty
+type bindSource func(key string) (values []string, ok bool)
+
+// bindTag is parsed field tag, e.g. `query:"sort,required,enum=asc|desc,default=asc"`. Default is the rest of the tag, so it may
+// contain comma
+type bindTag struct {
+	name     string
+	required bool
+	enum     []string
+	def      *string
+}
+
+func parseBindTag(tag string) bindTag {
+	parts := strings.Split(tag, ",")
+
+	t := bindTag{name: parts[0]}
+	for i, p := range parts[1:] {
+		switch {
+		case p == "required":
+			t.required = true
+		case strings.HasPrefix(p, "enum="):
+			t.enum = strings.Split(strings.TrimPrefix(p, "enum="), "|")
+		case strings.HasPrefix(p, "default="):
+			def := strings.Join(append([]string{strings.TrimPrefix(p, "default=")}, parts[i+2:]...), ",")
+			t.def = &def
+
+			return t
+		}
+	}
+
+	return t
+}
+
+// binderSource is source of values of fields tagged by tag
+type binderSource struct {
+	tag    string
+	source bindSource
+}
+
+// binder bind values of sources to struct fields tagged by tag of the sources
+type binder struct {
+	sources []binderSource
+}
+
+// bind bind the sources to dst, it return DefaultUnprocessableEntityErrorResponse with FieldErrors of every missing or invalid key,
+// or CoreError if dst or its fields are not supported
+func (b binder) bind(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return NewCoreError(fmt.Sprintf("bind: dst must be non-nil pointer to struct, got %T", dst))
+	}
+
+	var fes FieldErrors
+	if _, err := b.bindStruct(v.Elem(), "", map[reflect.Type]bool{}, &fes); err != nil {
+		return err
+	}
+
+	if len(fes) > 0 {
+		return DefaultUnprocessableEntityErrorResponse.SetError(fes)
+	}
+
+	return nil
+}
+
+// bindStruct bind fields of struct v, keys are prefixed by prefix. Fields of embedded structs are promoted, fields of tagged
+// structs are prefixed by the tag name, e.g. filter.status, untagged structs are skipped. Struct of type in visited, the structs
+// being bound, is skipped to stop recursive types. It return true if any field is bound
+func (b binder) bindStruct(v reflect.Value, prefix string, visited map[reflect.Type]bool, fes *FieldErrors) (bool, error) {
+	t := v.Type()
+	visited[t] = true
+	defer delete(visited, t)
+
+	bound := false
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fv := v.Field(i)
+
+		source, tag, tagged := b.fieldTag(f)
+		if tagged && tag.name == "-" {
+			continue
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if ft.Kind() == reflect.Struct && ft != timeType && ft != datetimeType {
+			if (!f.Anonymous && !tagged) || visited[ft] {
+				continue
+			}
+
+			// Pointer of unexported embedded struct can't be allocated, exported fields of unexported embedded struct are settable
+			if fv.Kind() == reflect.Ptr && !fv.CanSet() {
+				continue
+			}
+
+			p := prefix
+			if tagged && tag.name != "" {
+				p += tag.name + "."
+			}
+
+			// Nil pointer is allocated only if any field of the struct is bound
+			sv := fv
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					sv = reflect.New(ft)
+				}
+				sv = sv.Elem()
+			}
+
+			ok, err := b.bindStruct(sv, p, visited, fes)
+			if err != nil {
+				return false, err
+			}
+
+			if ok && fv.Kind() == reflect.Ptr && fv.IsNil() {
+				fv.Set(sv.Addr())
+			}
+			bound = bound || ok
+
+			continue
+		}
+
+		if !tagged || !fv.CanSet() {
+			continue
+		}
+
+		key := tag.name
+		if key == "" {
+			key = f.Name
+		}
+		key = prefix + key
+
+		values, ok := source(key)
+		if ok && ft.Kind() == reflect.Slice {
+			values = splitValues(values)
+			ok = len(values) > 0
+		}
+
+		if !ok {
+			if tag.def == nil {
+				if tag.required {
+					*fes = append(*fes, FieldError{Field: key, Rule: "required", Message: "is required"})
+				}
+
+				continue
+			}
+
+			values = []string{*tag.def}
+			if ft.Kind() == reflect.Slice {
+				values = splitValues(values)
+			}
+		}
+
+		fe, err := setBindValue(fv, values, tag.enum)
+		if err != nil {
+			return false, NewCoreError(fmt.Sprintf("bind: field %s.%s, %v", t.Name(), f.Name, err))
+		}
+
+		if fe != nil {
+			fe.Field = key
+			*fes = append(*fes, *fe)
+
+			continue
+		}
+
+		bound = true
+	}
+
+	return bound, nil
+}
+
+// fieldTag return source & parsed tag of the first source tagging f
+func (b binder) fieldTag(f reflect.StructField) (bindSource, bindTag, bool) {
+	for _, s := range b.sources {
+		if tag, ok := f.Tag.Lookup(s.tag); ok {
+			return s.source, parseBindTag(tag), true
+		}
+	}
+
+	return nil, bindTag{}, false
+}
+
+// setBindValue parse values to v, it return FieldError when values are invalid or error when type of v is not supported
+func setBindValue(v reflect.Value, values []string, enum []string) (*FieldError, error) {
+	t := v.Type()
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	pv, err := parseValues(t, values, enum)
+	if err != nil {
+		var ve valueError
+		if errors.As(err, &ve) {
+			return &FieldError{Rule: ve.rule, Param: ve.param, Message: ve.msg}, nil
+		}
+
+		return nil, err
+	}
+
+	if v.Kind() == reflect.Ptr {
+		n := reflect.New(t)
+		n.Elem().Set(pv)
+		pv = n
+	}
+
+	v.Set(pv)
+
+	return nil, nil
+}
+
+// valueError is violation of a value, e.g. rule type when it can't be parsed or rule enum when it is not allowed
+type valueError struct {
+	rule  string
+	param string
+	msg   string
+}
+
+func (e valueError) Error() string {
+	return e.msg
+}
+
+// parseValues parse values to value of type t, every value is parsed to element of slice or the first value is parsed otherwise.
+// Each value must be one of enum if it is not empty
+func parseValues(t reflect.Type, values []string, enum []string) (reflect.Value, error) {
+	for _, s := range values {
+		if len(enum) > 0 && !containsString(enum, s) {
+			return reflect.Value{}, valueError{
+				rule:  "enum",
+				param: strings.Join(enum, "|"),
+				msg:   fmt.Sprintf("must be one of %s, got '%s'", strings.Join(enum, ", "), s),
+			}
+		}
+	}
+
+	if t.Kind() != reflect.Slice {
+		return parseValue(t, values[0])
+	}
+
+	sv := reflect.MakeSlice(t, len(values), len(values))
+	for i, s := range values {
+		v, err := parseValue(t.Elem(), s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		sv.Index(i).Set(v)
+	}
+
+	return sv, nil
+}
+
+// parseValue parse s to value of type t. Integers, unsigned integers & floats are parsed with overflow check of size of t,
+// time.Duration by time.ParseDuration, time.Time & utils.Datetime as RFC3339 timestamp
+func parseValue(t reflect.Type, s string) (reflect.Value, error) {
+	var (
+		v   interface{}
+		err error
+	)
+
+	switch {
+	case t == durationType:
+		v, err = time.ParseDuration(s)
+	case t == timeType:
+		v, err = time.Parse(time.RFC3339, s)
+	case t == datetimeType:
+		var d *utils.Datetime
+		if d, err = utils.NewDatetimeFromString(s); err == nil {
+			v = *d
+		}
+	default:
+		switch t.Kind() {
+		case reflect.String:
+			v = s
+		case reflect.Bool:
+			v, err = strconv.ParseBool(s)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v, err = strconv.ParseInt(s, 10, t.Bits())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			v, err = strconv.ParseUint(s, 10, t.Bits())
+		case reflect.Float32, reflect.Float64:
+			v, err = strconv.ParseFloat(s, t.Bits())
+		default:
+			return reflect.Value{}, fmt.Errorf("%w %s", errUnsupportedType, t)
+		}
+	}
+
+	if err != nil {
+		name := valueTypeName(t)
+
+		return reflect.Value{}, valueError{rule: "type", param: name, msg: fmt.Sprintf("must be %s, got '%s'", name, s)}
+	}
+
+	return reflect.ValueOf(v).Convert(t), nil
+}
+
+// valueTypeName return name of type t in violation message
+func valueTypeName(t reflect.Type) string {
+	switch t {
+	case durationType:
+		return "duration"
+	case timeType, datetimeType:
+		return "RFC3339 time"
+	}
+
+	return t.Kind().String()
+}
+
+// splitValues split comma separated values, spaces around items are trimmed & empty items are dropped
+func splitValues(values []string) []string {
+	var items []string
+	for _, v := range values {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+	}
+
+	return items
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
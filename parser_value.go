@@ -0,0 +1,226 @@
+package noob
+
+import (
+	"fmt"
+	"github.com/alfarih31/nb-go-http/utils"
+	"reflect"
+	"time"
+)
+
+// valueParser parse values of keys from a part of the request, errors are prefixed by name, e.g. qs
+type valueParser struct {
+	name   string
+	source bindSource
+}
+
+func (p valueParser) getOptions(key string, opt []QueryParserOption) (interface{}, error) {
+	if len(opt) > 0 {
+		o := opt[0]
+		if o.Required {
+			if o.Default != nil {
+				return o.Default, nil
+			}
+
+			return nil, fmt.Errorf("%s: %s is required", p.name, key)
+		}
+	}
+
+	return nil, nil
+}
+
+func (p valueParser) getKeyErr(key string, err error) error {
+	return fmt.Errorf("%s: %s error, %v", p.name, key, err)
+}
+
+// get parse value of key to type t, nil if it is missing. Slice is parsed from repeated or comma separated values, e.g. ?id=1&id=2
+// or ?ids=1,2. Default is used when the key is missing or invalid only if the key is required
+func (p valueParser) get(key string, t reflect.Type, opt []QueryParserOption) (interface{}, error) {
+	values, ok := p.source(key)
+	if t.Kind() == reflect.Slice {
+		values = splitValues(values)
+	} else if ok && values[0] == "" {
+		values = nil
+	}
+
+	optVal, optErr := p.getOptions(key, opt)
+
+	if len(values) == 0 {
+		return optVal, optErr
+	}
+
+	var enum []string
+	if len(opt) > 0 {
+		enum = opt[0].Enum
+	}
+
+	v, err := parseValues(t, values, enum)
+	if err != nil {
+		if optErr == nil && optVal != nil {
+			return optVal, nil
+		}
+
+		return nil, p.getKeyErr(key, err)
+	}
+
+	return v.Interface(), nil
+}
+
+func (p valueParser) getString(key string, opt []QueryParserOption) (*string, error) {
+	v, err := p.get(key, reflect.TypeOf(""), opt)
+	if v == nil || err != nil {
+		return nil, err
+	}
+
+	s := v.(string)
+	return &s, nil
+}
+
+func (p valueParser) getBool(key string, opt []QueryParserOption) (*bool, error) {
+	v, err := p.get(key, reflect.TypeOf(false), opt)
+	if v == nil || err != nil {
+		return nil, err
+	}
+
+	b := v.(bool)
+	return &b, nil
+}
+
+func (p valueParser) getInt(key string, opt []QueryParserOption) (*int, error) {
+	v, err := p.get(key, reflect.TypeOf(0), opt)
+	if v == nil || err != nil {
+		return nil, err
+	}
+
+	i := v.(int)
+	return &i, nil
+}
+
+func (p valueParser) getInt32(key string, opt []QueryParserOption) (*int32, error) {
+	v, err := p.get(key, reflect.TypeOf(int32(0)), opt)
+	if v == nil || err != nil {
+		return nil, err
+	}
+
+	i := v.(int32)
+	return &i, nil
+}
+
+func (p valueParser) getInt64(key string, opt []QueryParserOption) (*int64, error) {
+	v, err := p.get(key, reflect.TypeOf(int64(0)), opt)
+	if v == nil || err != nil {
+		return nil, err
+	}
+
+	i := v.(int64)
+	return &i, nil
+}
+
+func (p valueParser) getFloat32(key string, opt []QueryParserOption) (*float32, error) {
+	v, err := p.get(key, reflect.TypeOf(float32(0)), opt)
+	if v == nil || err != nil {
+		return nil, err
+	}
+
+	f := v.(float32)
+	return &f, nil
+}
+
+func (p valueParser) getFloat64(key string, opt []QueryParserOption) (*float64, error) {
+	v, err := p.get(key, reflect.TypeOf(float64(0)), opt)
+	if v == nil || err != nil {
+		return nil, err
+	}
+
+	f := v.(float64)
+	return &f, nil
+}
+
+func (p valueParser) getUint(key string, opt []QueryParserOption) (*uint, error) {
+	v, err := p.get(key, reflect.TypeOf(uint(0)), opt)
+	if v == nil || err != nil {
+		return nil, err
+	}
+
+	u := v.(uint)
+	return &u, nil
+}
+
+func (p valueParser) getUint32(key string, opt []QueryParserOption) (*uint32, error) {
+	v, err := p.get(key, reflect.TypeOf(uint32(0)), opt)
+	if v == nil || err != nil {
+		return nil, err
+	}
+
+	u := v.(uint32)
+	return &u, nil
+}
+
+func (p valueParser) getUint64(key string, opt []QueryParserOption) (*uint64, error) {
+	v, err := p.get(key, reflect.TypeOf(uint64(0)), opt)
+	if v == nil || err != nil {
+		return nil, err
+	}
+
+	u := v.(uint64)
+	return &u, nil
+}
+
+func (p valueParser) getDuration(key string, opt []QueryParserOption) (*time.Duration, error) {
+	v, err := p.get(key, durationType, opt)
+	if v == nil || err != nil {
+		return nil, err
+	}
+
+	d := v.(time.Duration)
+	return &d, nil
+}
+
+func (p valueParser) getTime(key string, opt []QueryParserOption) (*utils.Datetime, error) {
+	v, err := p.get(key, datetimeType, opt)
+	if v == nil || err != nil {
+		return nil, err
+	}
+
+	if d, ok := v.(*utils.Datetime); ok {
+		return d, nil
+	}
+
+	d := v.(utils.Datetime)
+	return &d, nil
+}
+
+func (p valueParser) getStrings(key string, opt []QueryParserOption) ([]string, error) {
+	v, err := p.get(key, reflect.TypeOf([]string{}), opt)
+	if v == nil || err != nil {
+		return nil, err
+	}
+
+	return v.([]string), nil
+}
+
+func (p valueParser) getInts(key string, opt []QueryParserOption) ([]int, error) {
+	v, err := p.get(key, reflect.TypeOf([]int{}), opt)
+	if v == nil || err != nil {
+		return nil, err
+	}
+
+	return v.([]int), nil
+}
+
+func (p valueParser) getInt64s(key string, opt []QueryParserOption) ([]int64, error) {
+	v, err := p.get(key, reflect.TypeOf([]int64{}), opt)
+	if v == nil || err != nil {
+		return nil, err
+	}
+
+	return v.([]int64), nil
+}
+
+func (p valueParser) getFloat64s(key string, opt []QueryParserOption) ([]float64, error) {
+	v, err := p.get(key, reflect.TypeOf([]float64{}), opt)
+	if v == nil || err != nil {
+		return nil, err
+	}
+
+	return v.([]float64), nil
+}
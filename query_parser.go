@@ -2,8 +2,9 @@ package noob
 
 import (
 	"fmt"
+	"github.com/alfarih31/nb-go-http/utils"
 	keyvalue "github.com/alfarih31/nb-go-keyvalue"
-	"github.com/alfarih31/nb-go-parser"
+	"time"
 )
 
 type QueryParser HandlerCtx
@@ -11,6 +12,9 @@ type QueryParser HandlerCtx
 type QueryParserOption struct {
 	Default  interface{}
 	Required bool
+
+	// Enum is allowed values of the key, each value of slice is checked
+	Enum []string
 }
 
 type QueryValueType uint
@@ -21,6 +25,17 @@ const (
 	QueryValueTypeInt
 	QueryValueTypeInt32
 	QueryValueTypeInt64
+	QueryValueTypeFloat32
+	QueryValueTypeFloat64
+	QueryValueTypeUint
+	QueryValueTypeUint32
+	QueryValueTypeUint64
+	QueryValueTypeDuration
+	QueryValueTypeTime
+	QueryValueTypeStrings
+	QueryValueTypeInts
+	QueryValueTypeInt64s
+	QueryValueTypeFloat64s
 )
 
 type Query struct {
@@ -28,25 +43,7 @@ type Query struct {
 	Type     QueryValueType
 	Default  interface{}
 	Required bool
-}
-
-func getOptions(key string, opt []QueryParserOption) (interface{}, error) {
-	if len(opt) > 0 {
-		o := opt[0]
-		if o.Required {
-			if o.Default != nil {
-				return o.Default, nil
-			}
-
-			return nil, fmt.Errorf("qs: %s is required", key)
-		}
-	}
-
-	return nil, nil
-}
-
-func getKeyErr(key string, err error) error {
-	return fmt.Errorf("qs: %s error, %v", key, err)
+	Enum     []string
 }
 
 func (p QueryParser) GetQueries(target interface{}, qs []Query) error {
@@ -56,17 +53,44 @@ func (p QueryParser) GetQueries(target interface{}, qs []Query) error {
 			v   interface{}
 			err error
 		)
+		opt := QueryParserOption{Default: q.Default, Required: q.Required, Enum: q.Enum}
 		switch q.Type {
 		case QueryValueTypeString:
-			v, err = p.GetString(q.Key, QueryParserOption{Default: q.Default, Required: q.Required})
+			v, err = p.GetString(q.Key, opt)
 		case QueryValueTypeBool:
-			v, err = p.GetBool(q.Key, QueryParserOption{Default: q.Default, Required: q.Required})
+			v, err = p.GetBool(q.Key, opt)
 		case QueryValueTypeInt:
-			v, err = p.GetInt(q.Key, QueryParserOption{Default: q.Default, Required: q.Required})
+			v, err = p.GetInt(q.Key, opt)
 		case QueryValueTypeInt32:
-			v, err = p.GetInt32(q.Key, QueryParserOption{Default: q.Default, Required: q.Required})
+			v, err = p.GetInt32(q.Key, opt)
 		case QueryValueTypeInt64:
-			v, err = p.GetInt64(q.Key, QueryParserOption{Default: q.Default, Required: q.Required})
+			v, err = p.GetInt64(q.Key, opt)
+		case QueryValueTypeFloat32:
+			v, err = p.GetFloat32(q.Key, opt)
+		case QueryValueTypeFloat64:
+			v, err = p.GetFloat64(q.Key, opt)
+		case QueryValueTypeUint:
+			v, err = p.GetUint(q.Key, opt)
+		case QueryValueTypeUint32:
+			v, err = p.GetUint32(q.Key, opt)
+		case QueryValueTypeUint64:
+			v, err = p.GetUint64(q.Key, opt)
+		case QueryValueTypeDuration:
+			v, err = p.GetDuration(q.Key, opt)
+		case QueryValueTypeTime:
+			// Unmarshal to target as time.Time, utils.Datetime has no exported field
+			var d *utils.Datetime
+			if d, err = p.GetTime(q.Key, opt); d != nil {
+				v = d.GetTime()
+			}
+		case QueryValueTypeStrings:
+			v, err = p.GetStrings(q.Key, opt)
+		case QueryValueTypeInts:
+			v, err = p.GetInts(q.Key, opt)
+		case QueryValueTypeInt64s:
+			v, err = p.GetInt64s(q.Key, opt)
+		case QueryValueTypeFloat64s:
+			v, err = p.GetFloat64s(q.Key, opt)
 		default:
 			log.Warn(fmt.Sprintf("Unknown QueryValueType, Key=%s, Type=%d", q.Key, q.Type))
 		}
@@ -81,176 +105,100 @@ func (p QueryParser) GetQueries(target interface{}, qs []Query) error {
 	return kv.Unmarshal(target)
 }
 
-func (p QueryParser) GetString(key string, opt ...QueryParserOption) (*string, error) {
-	val := p.Query(key)
-
-	if val == "" {
-		optVal, err := getOptions(key, opt)
-		if err != nil {
-			return nil, err
-		}
-
-		if optVal != nil {
-			v := optVal.(string)
-			return &v, nil
-		}
+func (p QueryParser) parser() valueParser {
+	return valueParser{name: "qs", source: p.queryValues}
+}
 
-		return nil, nil
-	}
+func (p QueryParser) GetString(key string, opt ...QueryParserOption) (*string, error) {
+	return p.parser().getString(key, opt)
+}
 
-	return &val, nil
+func (p QueryParser) GetBool(key string, opt ...QueryParserOption) (*bool, error) {
+	return p.parser().getBool(key, opt)
 }
 
 func (p QueryParser) GetInt(key string, opt ...QueryParserOption) (*int, error) {
-	val := p.Query(key)
-
-	optVal, optErr := getOptions(key, opt)
-
-	if val == "" {
-		if optErr != nil {
-			return nil, optErr
-		}
-
-		if optVal != nil {
-			v := optVal.(int)
-
-			return &v, nil
-		}
-
-		return nil, nil
-	}
-
-	i, err := parser.String(val).ToInt()
-
-	if err != nil {
-		if optErr != nil {
-			return nil, getKeyErr(key, err)
-		}
-
-		if optVal != nil {
-			v := optVal.(int)
-
-			return &v, nil
-		}
-
-		return nil, getKeyErr(key, err)
-	}
-
-	return &i, err
+	return p.parser().getInt(key, opt)
 }
 
+// GetInt32 return value of key as int32, value out of int32 range is invalid
 func (p QueryParser) GetInt32(key string, opt ...QueryParserOption) (*int32, error) {
-	val := p.Query(key)
-
-	optVal, optErr := getOptions(key, opt)
-
-	if val == "" {
-		if optErr != nil {
-			return nil, optErr
-		}
-
-		if optVal != nil {
-			v := optVal.(int32)
-
-			return &v, nil
-		}
-
-		return nil, nil
-	}
-
-	i, err := parser.String(val).ToInt()
-	i32 := int32(i)
-
-	if err != nil {
-		if optErr != nil {
-			return nil, getKeyErr(key, err)
-		}
-
-		if optVal != nil {
-			v := optVal.(int32)
-
-			return &v, nil
-		}
-
-		return nil, getKeyErr(key, err)
-	}
-
-	return &i32, err
+	return p.parser().getInt32(key, opt)
 }
 
 func (p QueryParser) GetInt64(key string, opt ...QueryParserOption) (*int64, error) {
-	val := p.Query(key)
-
-	optVal, optErr := getOptions(key, opt)
-
-	if val == "" {
-		if optErr != nil {
-			return nil, optErr
-		}
-
-		if optVal != nil {
-			v := optVal.(int64)
-
-			return &v, nil
-		}
-
-		return nil, nil
-	}
-
-	i, err := parser.String(val).ToInt()
-	i64 := int64(i)
-
-	if err != nil {
-		if optErr != nil {
-			return nil, getKeyErr(key, err)
-		}
-
-		if optVal != nil {
-			v := optVal.(int64)
-
-			return &v, nil
-		}
+	return p.parser().getInt64(key, opt)
+}
 
-		return nil, getKeyErr(key, err)
-	}
+func (p QueryParser) GetFloat32(key string, opt ...QueryParserOption) (*float32, error) {
+	return p.parser().getFloat32(key, opt)
+}
 
-	return &i64, err
+func (p QueryParser) GetFloat64(key string, opt ...QueryParserOption) (*float64, error) {
+	return p.parser().getFloat64(key, opt)
 }
 
-func (p QueryParser) GetBool(key string, opt ...QueryParserOption) (*bool, error) {
-	val := p.Query(key)
+// GetUint return value of key as uint, negative value is invalid
+func (p QueryParser) GetUint(key string, opt ...QueryParserOption) (*uint, error) {
+	return p.parser().getUint(key, opt)
+}
 
-	optVal, optErr := getOptions(key, opt)
+func (p QueryParser) GetUint32(key string, opt ...QueryParserOption) (*uint32, error) {
+	return p.parser().getUint32(key, opt)
+}
 
-	if val == "" {
-		if optErr != nil {
-			return nil, optErr
-		}
+func (p QueryParser) GetUint64(key string, opt ...QueryParserOption) (*uint64, error) {
+	return p.parser().getUint64(key, opt)
+}
 
-		if optVal != nil {
-			v := optVal.(bool)
+// GetDuration return value of key parsed by time.ParseDuration, e.g. 1m30s
+func (p QueryParser) GetDuration(key string, opt ...QueryParserOption) (*time.Duration, error) {
+	return p.parser().getDuration(key, opt)
+}
 
-			return &v, nil
-		}
+// GetTime return value of key parsed as RFC3339 timestamp, e.g. 2006-01-02T15:04:05Z07:00. Default must be *utils.Datetime
+func (p QueryParser) GetTime(key string, opt ...QueryParserOption) (*utils.Datetime, error) {
+	return p.parser().getTime(key, opt)
+}
 
-		return nil, nil
-	}
+// GetStrings return repeated or comma separated values of key, e.g. ?tag=a&tag=b or ?tags=a,b
+func (p QueryParser) GetStrings(key string, opt ...QueryParserOption) ([]string, error) {
+	return p.parser().getStrings(key, opt)
+}
 
-	b, err := parser.String(val).ToBool()
+// GetInts return repeated or comma separated values of key as []int, e.g. ?id=1&id=2 or ?ids=1,2
+func (p QueryParser) GetInts(key string, opt ...QueryParserOption) ([]int, error) {
+	return p.parser().getInts(key, opt)
+}
 
-	if err != nil {
-		if optErr != nil {
-			return nil, getKeyErr(key, err)
-		}
+func (p QueryParser) GetInt64s(key string, opt ...QueryParserOption) ([]int64, error) {
+	return p.parser().getInt64s(key, opt)
+}
 
-		if optVal != nil {
-			v := optVal.(bool)
+func (p QueryParser) GetFloat64s(key string, opt ...QueryParserOption) ([]float64, error) {
+	return p.parser().getFloat64s(key, opt)
+}
 
-			return &v, nil
-		}
+// Bind bind query of the request to fields of dst tagged by `query:"name,required,enum=a|b,default=10"`, fields without the tag
+// are skipped. Fields of embedded structs, e.g. pagination, are promoted, fields of tagged structs are keyed by the tag name as
+// prefix, e.g. filter.status, untagged structs are skipped. Nil pointer to struct is allocated only if any of its fields is bound
+// & recursive types are bound once. Default is the rest of the tag, so it must be the last option.
+//
+// Supported types are string, bool, integers, unsigned integers & floats with overflow check, time.Duration, time.Time &
+// utils.Datetime parsed as RFC3339, named types of them & pointers to them. Slice is bound from repeated or comma separated
+// values, e.g. ?id=1&id=2 or ?ids=1,2. Pointer is nil when the key is missing without default.
+//
+// Every missing required or invalid key is reported at once in FieldErrors of DefaultUnprocessableEntityErrorResponse
+func (p QueryParser) Bind(dst interface{}) error {
+	return binder{sources: []binderSource{{tag: "query", source: p.queryValues}}}.bind(dst)
+}
 
-		return nil, getKeyErr(key, err)
+// queryValues return values of key, empty values are missing
+func (p QueryParser) queryValues(key string) ([]string, bool) {
+	values, ok := p.GetQueryArray(key)
+	if !ok || len(values) == 0 || (len(values) == 1 && values[0] == "") {
+		return nil, false
 	}
 
-	return &b, err
+	return values, true
 }
@@ -0,0 +1,341 @@
+package noob
+
+import (
+	"errors"
+	"github.com/alfarih31/nb-go-http/utils"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type queryTestPagination struct {
+	Page  int `query:"page,default=1"`
+	Limit int `query:"limit,default=10"`
+}
+
+type queryTestFilter struct {
+	Status string `query:"status,required"`
+	Active *bool  `query:"active"`
+}
+
+type queryTestList struct {
+	queryTestPagination
+
+	Search  string          `query:"q"`
+	Sort    string          `query:"sort,default=name,-created_at"`
+	Offset  *int64          `query:"offset"`
+	Count   int32           `query:"count"`
+	Filter  queryTestFilter `query:"filter"`
+	Ignored string          `query:"-"`
+	Plain   string
+}
+
+func bindQuery(t *testing.T, target string, dst interface{}) error {
+	t.Helper()
+
+	ec, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ec.Request = httptest.NewRequest(http.MethodGet, target, nil)
+
+	return QueryParser(*WrapHandlerCtx(ec)).Bind(dst)
+}
+
+func TestQueryParserBind(t *testing.T) {
+	var q queryTestList
+	err := bindQuery(t, "/?q=bob&offset=20&count=3&filter.status=open&filter.active=true&limit=50&Ignored=x&Plain=x", &q)
+	if err != nil {
+		t.Fatalf("bind error: %v", err)
+	}
+
+	if q.Search != "bob" || q.Offset == nil || *q.Offset != 20 || q.Count != 3 {
+		t.Fatalf("expected bound values, got %+v", q)
+	}
+
+	if q.Filter.Status != "open" || q.Filter.Active == nil || !*q.Filter.Active {
+		t.Fatalf("expected bound nested struct, got %+v", q.Filter)
+	}
+
+	// Embedded struct is promoted
+	if q.Page != 1 || q.Limit != 50 {
+		t.Fatalf("expected bound pagination, got %+v", q.queryTestPagination)
+	}
+
+	// Default may contain comma
+	if q.Sort != "name,-created_at" {
+		t.Fatalf("expected default sort, got %s", q.Sort)
+	}
+
+	if q.Ignored != "" || q.Plain != "" {
+		t.Fatalf("expected untagged fields skipped, got %+v", q)
+	}
+}
+
+func TestQueryParserBindMissing(t *testing.T) {
+	var q queryTestList
+	if err := bindQuery(t, "/?filter.status=open&q=", &q); err != nil {
+		t.Fatalf("bind error: %v", err)
+	}
+
+	if q.Offset != nil || q.Filter.Active != nil || q.Search != "" {
+		t.Fatalf("expected missing values unset, got %+v", q)
+	}
+}
+
+func TestQueryParserBindErrors(t *testing.T) {
+	var q queryTestList
+	err := bindQuery(t, "/?page=first&count=3000000000&filter.active=maybe", &q)
+
+	var re ResponseError
+	if !errors.As(err, &re) || *re.GetCode() != StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %v", err)
+	}
+
+	// Every violation is reported
+	want := []FieldError{
+		{Field: "page", Rule: "type", Param: "int", Message: "must be int, got 'first'"},
+		{Field: "count", Rule: "type", Param: "int32", Message: "must be int32, got '3000000000'"},
+		{Field: "filter.status", Rule: "required", Message: "is required"},
+		{Field: "filter.active", Rule: "type", Param: "bool", Message: "must be bool, got 'maybe'"},
+	}
+
+	fes, ok := re.GetBody().Errors.([]interface{})
+	if !ok || len(fes) != len(want) {
+		t.Fatalf("expected %d field errors, got %v", len(want), re.GetBody().Errors)
+	}
+
+	for i, fe := range want {
+		got := fes[i].(map[string]interface{})
+		if got["field"] != fe.Field || got["rule"] != fe.Rule || got["message"] != fe.Message {
+			t.Fatalf("expected %v, got %v", fe, got)
+		}
+	}
+}
+
+func TestQueryParserBindInvalidTarget(t *testing.T) {
+	var q queryTestList
+	if err := bindQuery(t, "/", q); err == nil {
+		t.Fatal("expected error of non-pointer dst")
+	}
+
+	var unsupported struct {
+		Values map[string]string `query:"values"`
+	}
+	if err := bindQuery(t, "/?values=1", &unsupported); err == nil {
+		t.Fatal("expected error of unsupported type")
+	}
+}
+
+func queryParser(target string) QueryParser {
+	ec, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ec.Request = httptest.NewRequest(http.MethodGet, target, nil)
+
+	return QueryParser(*WrapHandlerCtx(ec))
+}
+
+func TestQueryParserGetters(t *testing.T) {
+	p := queryParser("/?ratio=0.5&size=42&ttl=1m30s&since=2022-01-02T15:04:05Z&ids=1,2&ids=3&tags=a,,b")
+
+	if f, err := p.GetFloat64("ratio"); err != nil || *f != 0.5 {
+		t.Fatalf("expected ratio 0.5, got %v %v", f, err)
+	}
+
+	if u, err := p.GetUint32("size"); err != nil || *u != 42 {
+		t.Fatalf("expected size 42, got %v %v", u, err)
+	}
+
+	if d, err := p.GetDuration("ttl"); err != nil || *d != 90*time.Second {
+		t.Fatalf("expected ttl 1m30s, got %v %v", d, err)
+	}
+
+	if d, err := p.GetTime("since"); err != nil || d.ToString() != "2022-01-02T15:04:05Z" {
+		t.Fatalf("expected since, got %v %v", d, err)
+	}
+
+	// Repeated & comma separated values are merged
+	if ids, err := p.GetInts("ids"); err != nil || !reflect.DeepEqual(ids, []int{1, 2, 3}) {
+		t.Fatalf("expected ids, got %v %v", ids, err)
+	}
+
+	if tags, err := p.GetStrings("tags"); err != nil || !reflect.DeepEqual(tags, []string{"a", "b"}) {
+		t.Fatalf("expected tags, got %v %v", tags, err)
+	}
+
+	if ids, err := p.GetInt64s("missing"); err != nil || ids != nil {
+		t.Fatalf("expected missing ids, got %v %v", ids, err)
+	}
+}
+
+func TestQueryParserOverflow(t *testing.T) {
+	p := queryParser("/?i32=3000000000&u=-1&u8=256&f32=1e39&ids=1,99999999999999999999")
+
+	if _, err := p.GetInt32("i32"); err == nil {
+		t.Fatal("expected overflow error of int32")
+	}
+
+	if _, err := p.GetUint("u"); err == nil {
+		t.Fatal("expected error of negative uint")
+	}
+
+	if _, err := p.GetFloat32("f32"); err == nil {
+		t.Fatal("expected overflow error of float32")
+	}
+
+	if _, err := p.GetInt64s("ids"); err == nil {
+		t.Fatal("expected overflow error of int64 item")
+	}
+
+	// Default of required key is used when the value is invalid
+	i, err := p.GetInt32("i32", QueryParserOption{Required: true, Default: int32(10)})
+	if err != nil || *i != 10 {
+		t.Fatalf("expected default 10, got %v %v", i, err)
+	}
+}
+
+func TestQueryParserEnum(t *testing.T) {
+	p := queryParser("/?sort=asc&status=open,closed&order=up")
+	enum := []string{"asc", "desc"}
+
+	if s, err := p.GetString("sort", QueryParserOption{Enum: enum}); err != nil || *s != "asc" {
+		t.Fatalf("expected sort asc, got %v %v", s, err)
+	}
+
+	if _, err := p.GetString("order", QueryParserOption{Enum: enum}); err == nil || !strings.Contains(err.Error(), "must be one of asc, desc") {
+		t.Fatalf("expected enum error, got %v", err)
+	}
+
+	if _, err := p.GetStrings("status", QueryParserOption{Enum: []string{"open"}}); err == nil {
+		t.Fatal("expected enum error of item")
+	}
+
+	if _, err := p.GetString("missing", QueryParserOption{Enum: enum, Required: true}); err == nil {
+		t.Fatal("expected required error")
+	}
+}
+
+func TestQueryParserGetQueries(t *testing.T) {
+	p := queryParser("/?ttl=5s&since=2022-01-02T15:04:05Z&ids=1,2&ratio=0.25")
+
+	var q struct {
+		TTL   time.Duration `json:"ttl"`
+		Since time.Time     `json:"since"`
+		IDs   []int64       `json:"ids"`
+		Ratio float32       `json:"ratio"`
+		Limit uint          `json:"limit"`
+	}
+	err := p.GetQueries(&q, []Query{
+		{Key: "ttl", Type: QueryValueTypeDuration},
+		{Key: "since", Type: QueryValueTypeTime},
+		{Key: "ids", Type: QueryValueTypeInt64s},
+		{Key: "ratio", Type: QueryValueTypeFloat32},
+		{Key: "limit", Type: QueryValueTypeUint, Required: true, Default: uint(20)},
+	})
+	if err != nil {
+		t.Fatalf("get queries error: %v", err)
+	}
+
+	if q.TTL != 5*time.Second || q.Since.Year() != 2022 || !reflect.DeepEqual(q.IDs, []int64{1, 2}) || q.Ratio != 0.25 || q.Limit != 20 {
+		t.Fatalf("expected queries, got %+v", q)
+	}
+}
+
+type queryTestSearch struct {
+	IDs    []uint          `query:"id"`
+	Tags   []string        `query:"tags,default=a,b"`
+	Sort   string          `query:"sort,enum=asc|desc,default=asc"`
+	Status []string        `query:"status,enum=open|closed"`
+	Ratio  float64         `query:"ratio"`
+	TTL    *time.Duration  `query:"ttl"`
+	Since  time.Time       `query:"since"`
+	Until  *utils.Datetime `query:"until"`
+}
+
+func TestQueryParserBindTypes(t *testing.T) {
+	var q queryTestSearch
+	err := bindQuery(t, "/?id=1&id=2,3&status=open,closed&ratio=0.5&ttl=2s&since=2022-01-02T15:04:05Z&until=2022-02-02T00:00:00Z", &q)
+	if err != nil {
+		t.Fatalf("bind error: %v", err)
+	}
+
+	if !reflect.DeepEqual(q.IDs, []uint{1, 2, 3}) || !reflect.DeepEqual(q.Status, []string{"open", "closed"}) {
+		t.Fatalf("expected bound slices, got %+v", q)
+	}
+
+	// Default of slice is comma separated
+	if !reflect.DeepEqual(q.Tags, []string{"a", "b"}) || q.Sort != "asc" {
+		t.Fatalf("expected defaults, got %+v", q)
+	}
+
+	if q.Ratio != 0.5 || q.TTL == nil || *q.TTL != 2*time.Second || q.Since.Day() != 2 || q.Until == nil || q.Until.GetTime().Month() != 2 {
+		t.Fatalf("expected bound values, got %+v", q)
+	}
+}
+
+func TestQueryParserBindTypeErrors(t *testing.T) {
+	var q queryTestSearch
+	err := bindQuery(t, "/?id=1,-2&sort=up&status=open,draft&ttl=soon&since=yesterday", &q)
+
+	var re ResponseError
+	if !errors.As(err, &re) || *re.GetCode() != StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %v", err)
+	}
+
+	want := []FieldError{
+		{Field: "id", Rule: "type", Param: "uint", Message: "must be uint, got '-2'"},
+		{Field: "sort", Rule: "enum", Param: "asc|desc", Message: "must be one of asc, desc, got 'up'"},
+		{Field: "status", Rule: "enum", Param: "open|closed", Message: "must be one of open, closed, got 'draft'"},
+		{Field: "ttl", Rule: "type", Param: "duration", Message: "must be duration, got 'soon'"},
+		{Field: "since", Rule: "type", Param: "RFC3339 time", Message: "must be RFC3339 time, got 'yesterday'"},
+	}
+
+	fes, ok := re.GetBody().Errors.([]interface{})
+	if !ok || len(fes) != len(want) {
+		t.Fatalf("expected %d field errors, got %v", len(want), re.GetBody().Errors)
+	}
+
+	for i, fe := range want {
+		got := fes[i].(map[string]interface{})
+		if got["field"] != fe.Field || got["rule"] != fe.Rule || got["param"] != fe.Param || got["message"] != fe.Message {
+			t.Fatalf("expected %v, got %v", fe, got)
+		}
+	}
+}
+
+type queryTestMeta struct {
+	Owner string `query:"owner"`
+}
+
+type queryTestNode struct {
+	Name string         `query:"name"`
+	Next *queryTestNode `query:"next"`
+	Meta *queryTestMeta
+}
+
+func TestQueryParserBindUntaggedStruct(t *testing.T) {
+	var q queryTestNode
+	if err := bindQuery(t, "/?name=a&owner=bob&next.name=b", &q); err != nil {
+		t.Fatalf("bind error: %v", err)
+	}
+
+	// Untagged struct is skipped & recursive type is bound once, so Next is nil
+	if q.Name != "a" || q.Meta != nil || q.Next != nil {
+		t.Fatalf("expected only name bound, got %+v", q)
+	}
+}
+
+func TestQueryParserBindNilPointerStruct(t *testing.T) {
+	var q struct {
+		Filter *queryTestFilter `query:"filter"`
+		Meta   *queryTestMeta   `query:"meta"`
+	}
+	if err := bindQuery(t, "/?filter.status=open", &q); err != nil {
+		t.Fatalf("bind error: %v", err)
+	}
+
+	// Pointer is allocated only if any of its fields is bound
+	if q.Filter == nil || q.Filter.Status != "open" || q.Meta != nil {
+		t.Fatalf("expected only filter allocated, got %+v", q)
+	}
+}
@@ -0,0 +1,28 @@
+package noob
+
+// RequestParser parse every part of the request, path parameters, query, headers & cookies
+type RequestParser HandlerCtx
+
+// Bind bind the request to fields of dst tagged by `path:"id"`, `query:"limit"`, `header:"X-Tenant-ID"` or `cookie:"session"`
+// with the same rules as QueryParser.Bind, field tagged by more than one is bound by the first tag in that order.
+//
+// Every missing required or invalid key of every part is reported at once in FieldErrors of
+// DefaultUnprocessableEntityErrorResponse
+func (p RequestParser) Bind(dst interface{}) error {
+	return binder{sources: []binderSource{
+		{tag: "path", source: ParamParser(p).paramValues},
+		{tag: "query", source: QueryParser(p).queryValues},
+		{tag: "header", source: HeaderParser(p).headerValues},
+		{tag: "cookie", source: p.cookieValues},
+	}}.bind(dst)
+}
+
+// cookieValues return unescaped value of cookie key, empty value is missing
+func (p RequestParser) cookieValues(key string) ([]string, bool) {
+	v, err := p.Cookie(key)
+	if err != nil || v == "" {
+		return nil, false
+	}
+
+	return []string{v}, true
+}
@@ -0,0 +1,166 @@
+package noob
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type requestTestTarget struct {
+	ID      int64    `path:"id"`
+	Limit   int      `query:"limit,default=10"`
+	Tenant  string   `header:"X-Tenant-ID,required"`
+	Accept  []string `header:"Accept-Encoding"`
+	Session string   `cookie:"session"`
+}
+
+type requestTestTree struct {
+	ID       int64  `path:"id"`
+	Tenant   string `header:"X-Tenant-ID"`
+	Parent   *requestTestTree
+	Children []*requestTestTree
+	Owner    *struct {
+		Name string `query:"owner"`
+	}
+}
+
+func newRequestParserTestApp(t *testing.T) http.Handler {
+	t.Helper()
+
+	app := NewWithOptions()
+	app.GET("/users/:id", func(c *HandlerCtx) (Response, error) {
+		var r requestTestTarget
+		if err := RequestParser(*c).Bind(&r); err != nil {
+			return nil, err
+		}
+
+		return NewResponseSuccess(ResponseBody{Data: r}), nil
+	})
+	app.GET("/trees/:id", func(c *HandlerCtx) (Response, error) {
+		var r requestTestTree
+		if err := RequestParser(*c).Bind(&r); err != nil {
+			return nil, err
+		}
+
+		return NewResponseSuccess(ResponseBody{Data: map[string]interface{}{
+			"id": r.ID, "tenant": r.Tenant, "parent": r.Parent != nil, "owner": r.Owner != nil,
+		}}), nil
+	})
+	app.GET("/items/:ids", func(c *HandlerCtx) (Response, error) {
+		ids, err := ParamParser(*c).GetInts("ids")
+		if err != nil {
+			return nil, DefaultBadRequestErrorResponse.SetMessage(err.Error())
+		}
+
+		ttl, err := HeaderParser(*c).GetDuration("x-cache-ttl", QueryParserOption{Required: true, Default: time.Minute})
+		if err != nil {
+			return nil, DefaultBadRequestErrorResponse.SetMessage(err.Error())
+		}
+
+		return NewResponseSuccess(ResponseBody{Data: map[string]interface{}{"ids": ids, "ttl": ttl.String()}}), nil
+	})
+
+	h, err := app.Handler()
+	if err != nil {
+		t.Fatalf("handler error: %v", err)
+	}
+
+	return h
+}
+
+func serveRequestParser(t *testing.T, h http.Handler, target string, header http.Header) (int, map[string]interface{}) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header = header
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body error: %v, body: %s", err, rec.Body.String())
+	}
+
+	return rec.Code, body
+}
+
+func TestRequestParserBind(t *testing.T) {
+	h := newRequestParserTestApp(t)
+
+	code, body := serveRequestParser(t, h, "/users/7?limit=5", http.Header{
+		"X-Tenant-Id":     {"acme"},
+		"Accept-Encoding": {"gzip, br"},
+		"Cookie":          {"session=abc%20def"},
+	})
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d %v", code, body)
+	}
+
+	want := map[string]interface{}{
+		"ID":      float64(7),
+		"Limit":   float64(5),
+		"Tenant":  "acme",
+		"Accept":  []interface{}{"gzip", "br"},
+		"Session": "abc def",
+	}
+	if !reflect.DeepEqual(body["data"], want) {
+		t.Fatalf("expected %v, got %v", want, body["data"])
+	}
+}
+
+func TestRequestParserBindErrors(t *testing.T) {
+	h := newRequestParserTestApp(t)
+
+	code, body := serveRequestParser(t, h, "/users/first?limit=many", http.Header{})
+	if code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d %v", code, body)
+	}
+
+	// Violations of every part are reported at once
+	fes, _ := body["_error"].([]interface{})
+	var fields []string
+	for _, fe := range fes {
+		fields = append(fields, fe.(map[string]interface{})["field"].(string))
+	}
+
+	if !reflect.DeepEqual(fields, []string{"id", "limit", "X-Tenant-ID"}) {
+		t.Fatalf("expected violations of id, limit & X-Tenant-ID, got %v", body["_error"])
+	}
+}
+
+func TestParamHeaderParser(t *testing.T) {
+	h := newRequestParserTestApp(t)
+
+	code, body := serveRequestParser(t, h, "/items/1,2", http.Header{"X-Cache-Ttl": {"5s"}})
+	data, _ := body["data"].(map[string]interface{})
+	if code != http.StatusOK || !reflect.DeepEqual(data["ids"], []interface{}{float64(1), float64(2)}) || data["ttl"] != "5s" {
+		t.Fatalf("expected parsed ids & ttl, got %d %v", code, body)
+	}
+
+	// Default of required header is used when the value is invalid
+	code, body = serveRequestParser(t, h, "/items/3", http.Header{"X-Cache-Ttl": {"soon"}})
+	data, _ = body["data"].(map[string]interface{})
+	if code != http.StatusOK || data["ttl"] != "1m0s" {
+		t.Fatalf("expected default ttl, got %d %v", code, body)
+	}
+
+	code, body = serveRequestParser(t, h, "/items/1,x", http.Header{})
+	if code != http.StatusBadRequest || body["message"] != "path: ids error, must be int, got 'x'" {
+		t.Fatalf("expected error of ids, got %d %v", code, body)
+	}
+}
+
+func TestRequestParserBindNestedUntagged(t *testing.T) {
+	h := newRequestParserTestApp(t)
+
+	// Recursive & untagged struct pointers are left nil
+	code, body := serveRequestParser(t, h, "/trees/3?owner=bob", http.Header{"X-Tenant-Id": {"acme"}})
+	want := map[string]interface{}{"id": float64(3), "tenant": "acme", "parent": false, "owner": false}
+	if code != http.StatusOK || !reflect.DeepEqual(body["data"], want) {
+		t.Fatalf("expected %v, got %d %v", want, code, body)
+	}
+}
@@ -33,14 +33,18 @@ type ResponseBody struct {
 	Message string      `json:"message,omitempty"`
 	Data    interface{} `json:"data,omitempty"`
 	Errors  interface{} `json:"_error,omitempty"`
+
+	// RequestID is ID of the request, set on error responses
+	RequestID string `json:"request_id,omitempty"`
 }
 
 func (b ResponseBody) Copy() *ResponseBody {
 	return &ResponseBody{
-		Code:    b.Code,
-		Message: b.Message,
-		Data:    b.Data,
-		Errors:  b.Errors,
+		Code:      b.Code,
+		Message:   b.Message,
+		Data:      b.Data,
+		Errors:    b.Errors,
+		RequestID: b.RequestID,
 	}
 }
 